@@ -0,0 +1,25 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// drawBaseAlert pulses a red ring around the bases while an enemy is close,
+// brighter, wider, and faster the closer it gets
+func (g *Game) drawBaseAlert(screen *ebiten.Image) {
+	if g.BaseAlert == 0 {
+		return
+	}
+	pulse := 0.5 + 0.5*math.Sin(float64(g.Tick)*(0.1+0.3*g.BaseAlert))
+	c := color.RGBA{R: 255, A: uint8(255 * (0.3 + 0.7*g.BaseAlert) * pulse)}
+	r := float32(game.CellSize) * float32(0.7+0.3*pulse)
+	for _, b := range g.Bases {
+		cx, cy := game.CellCenter(b.X, b.Y)
+		vector.StrokeCircle(screen, float32(cx), float32(cy), r, float32(2+4*g.BaseAlert), c, true)
+	}
+}
@@ -0,0 +1,110 @@
+//go:build !noaudio
+
+package main
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	SampleRate = 44100
+
+	ShotDuration      = 0.08 // Seconds
+	ShotBaseFreq      = 880  // Hz at the start of the shot sweep
+	ShotPitchVariants = 5    // Pre-pitched shot buffers to choose from
+	ShotPitchSpread   = 0.08 // Max pitch deviation (+/- 8%)
+
+	AlarmDuration = 0.15 // Seconds
+	AlarmFreq     = 440  // Hz
+)
+
+// soundBoard plays synthesized sound effects
+type soundBoard struct {
+	ctx    *audio.Context
+	shots  [][]byte   // Shot sound at each pitch variant
+	alarm  []byte     // Base warning beep
+	rng    *rand.Rand // Picks pitch variants; separate from game logic
+	volume float64    // Applied to every new player
+}
+
+// newSoundBoard creates the audio context and pre-renders all sounds
+func newSoundBoard(seed uint64) *soundBoard {
+	s := &soundBoard{
+		ctx:    audio.NewContext(SampleRate),
+		rng:    rand.New(rand.NewPCG(seed, seed)),
+		volume: 1,
+	}
+	// Resampling per shot is too slow for rapid fire, so render each pitch once
+	for i := 0; i < ShotPitchVariants; i++ {
+		t := float64(i)/float64(ShotPitchVariants-1)*2 - 1 // -1..1
+		s.shots = append(s.shots, synthShot(1+t*ShotPitchSpread))
+	}
+	s.alarm = synthAlarm()
+	return s
+}
+
+// setVolume sets the volume used for sounds played from now on
+func (s *soundBoard) setVolume(v float64) {
+	if s == nil {
+		return
+	}
+	s.volume = v
+}
+
+// playShot plays a tower shot at a randomly chosen pitch
+func (s *soundBoard) playShot() {
+	if s == nil || s.volume <= 0 {
+		return
+	}
+	buf := s.shots[s.rng.IntN(len(s.shots))]
+	p := s.ctx.NewPlayerFromBytes(buf)
+	p.SetVolume(s.volume)
+	p.Play()
+}
+
+// playAlarm plays the base warning beep
+func (s *soundBoard) playAlarm() {
+	if s == nil || s.volume <= 0 {
+		return
+	}
+	p := s.ctx.NewPlayerFromBytes(s.alarm)
+	p.SetVolume(s.volume)
+	p.Play()
+}
+
+// synthShot renders a short descending "pew" as 16-bit stereo PCM
+func synthShot(pitch float64) []byte {
+	n := int(ShotDuration * SampleRate)
+	buf := make([]byte, 0, n*4)
+	phase := 0.0
+	for i := 0; i < n; i++ {
+		progress := float64(i) / float64(n)
+		freq := ShotBaseFreq * pitch * (1 - 0.5*progress) // Sweep down an octave
+		phase += 2 * math.Pi * freq / SampleRate
+		envelope := math.Exp(-5 * progress)
+		v := int16(math.Sin(phase) * envelope * 0.3 * math.MaxInt16)
+		// Same sample on left and right channel, little-endian
+		buf = append(buf, byte(v), byte(v>>8), byte(v), byte(v>>8))
+	}
+	return buf
+}
+
+// synthAlarm renders a flat square-wave beep as 16-bit stereo PCM, harsh
+// enough to stand out from the shots
+func synthAlarm() []byte {
+	n := int(AlarmDuration * SampleRate)
+	buf := make([]byte, 0, n*4)
+	for i := 0; i < n; i++ {
+		progress := float64(i) / float64(n)
+		amp := 0.2 * math.Min(1, (1-progress)*10) // Short fade out to avoid a click
+		if math.Sin(2*math.Pi*AlarmFreq*float64(i)/SampleRate) < 0 {
+			amp = -amp
+		}
+		v := int16(amp * math.MaxInt16)
+		buf = append(buf, byte(v), byte(v>>8), byte(v), byte(v>>8))
+	}
+	return buf
+}
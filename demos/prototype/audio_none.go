@@ -0,0 +1,14 @@
+//go:build noaudio
+
+package main
+
+// soundBoard is a silent stand-in used when built with the noaudio tag
+type soundBoard struct{}
+
+func newSoundBoard(seed uint64) *soundBoard { return &soundBoard{} }
+
+func (s *soundBoard) setVolume(v float64) {}
+
+func (s *soundBoard) playShot() {}
+
+func (s *soundBoard) playAlarm() {}
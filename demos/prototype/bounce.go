@@ -0,0 +1,25 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var bounceColor = color.RGBA{R: 255, G: 255, B: 255, A: 180}
+
+// drawBouncePads marks bounce pads with a chevron, hidden while recharging
+func (g *Game) drawBouncePads(screen *ebiten.Image) {
+	for _, t := range g.Towers {
+		if !game.TowerTypes[t.Kind].Bounce || t.Cooldown > 0 {
+			continue
+		}
+		cx, cy := game.CellCenter(t.X, t.Y)
+		x, y := float32(cx), float32(cy)
+		const arm = game.CellSize / 5
+		vector.StrokeLine(screen, x-arm, y+arm/2, x, y-arm/2, 3, bounceColor, true)
+		vector.StrokeLine(screen, x, y-arm/2, x+arm, y+arm/2, 3, bounceColor, true)
+	}
+}
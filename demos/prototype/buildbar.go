@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+const (
+	BuildBarLines  = 4  // Text lines per build bar slot
+	BuildBarHeight = 68 // Strip below the board reserved for the bar (fits it at text scale 1)
+)
+
+// buildKeys are the hotkeys of the first tower kinds, in build bar order:
+// the number row, 1 through 9 then 0
+var buildKeys = []ebiten.Key{
+	ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4, ebiten.Key5,
+	ebiten.Key6, ebiten.Key7, ebiten.Key8, ebiten.Key9, ebiten.Key0,
+}
+
+var buildBarColor = color.RGBA{R: 15, G: 15, B: 25, A: 220}
+
+var unaffordableColor = color.RGBA{R: 0, G: 0, B: 0, A: 150}
+
+var selectedSlotColor = color.RGBA{R: 255, G: 255, B: 255, A: 230}
+
+// buildBarHeight returns the height of the build bar along the bottom of the
+// screen. Larger text makes it grow up over the board.
+func (g *Game) buildBarHeight() int {
+	return max(BuildBarHeight, BuildBarLines*g.lineHeight()+4)
+}
+
+// buildBarSlot returns the screen rectangle of a tower kind's slot
+func (g *Game) buildBarSlot(kind game.TowerKind) (x, y, w, h int) {
+	w = g.screenWidth() / len(game.TowerTypes)
+	h = g.buildBarHeight()
+	return int(kind) * w, g.screenHeight() - h, w, h
+}
+
+// handleBuildBarInput selects the clicked (or tapped) tower kind. Returns
+// true if the pointer is over the bar, so the board ignores it.
+func (g *Game) handleBuildBarInput(sx, sy int, tap bool) bool {
+	if sy < g.screenHeight()-g.buildBarHeight() {
+		return false
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || tap {
+		_, _, w, _ := g.buildBarSlot(0)
+		g.selectBuildKind(game.TowerKind(min(sx/w, len(game.TowerTypes)-1)))
+	}
+	return true
+}
+
+// selectBuildKind sets the tower kind that placing builds
+func (g *Game) selectBuildKind(kind game.TowerKind) {
+	g.selectedKind = kind
+	g.ShowMessage("Building: %s (%d)", game.TowerTypes[kind].Name, game.TowerTypes[kind].Cost)
+}
+
+// drawBuildBar draws a slot per tower kind with its hotkey, cost, stats,
+// and special ability. Kinds the player can't afford are greyed out.
+func (g *Game) drawBuildBar(screen *ebiten.Image) {
+	height := g.buildBarHeight()
+	top := g.screenHeight() - height
+	vector.DrawFilledRect(screen, 0, float32(top), float32(g.screenWidth()), float32(height), buildBarColor, false)
+
+	lh := g.lineHeight()
+	for i, tt := range game.TowerTypes {
+		kind := game.TowerKind(i)
+		x, y, w, h := g.buildBarSlot(kind)
+		swatch := float32(lh - 4)
+		vector.DrawFilledRect(screen, float32(x+4), float32(y+4), swatch, swatch, tt.Color, false)
+
+		name := tt.Name
+		if i < len(buildKeys) {
+			name = fmt.Sprintf("%d %s", (i+1)%10, tt.Name) // Number key hotkey
+		}
+		lines := []string{
+			name,
+			fmt.Sprintf("Cost %d", tt.Cost),
+			fmt.Sprintf("DPS %.1f RNG %.1f", tt.DPS(), tt.Range/game.CellSize),
+			tt.Special,
+		}
+		if tt.Damage == 0 {
+			lines[2] = fmt.Sprintf("RNG %.1f", tt.Range/game.CellSize)
+		}
+		for j, l := range lines {
+			lx := x + 4
+			if j == 0 {
+				lx += lh // Beside the swatch
+			}
+			g.drawText(screen, l, lx, y+2+j*lh)
+		}
+
+		if g.Resources < tt.Cost {
+			vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), unaffordableColor, false)
+		}
+		if kind == g.selectedKind {
+			vector.StrokeRect(screen, float32(x+1), float32(y+1), float32(w-2), float32(h-2), 2, selectedSlotColor, false)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+const GhostAlpha = 70 // Opacity of queued placements' ghosts
+
+// drawBuildQueue draws queued placements as faint ghosts of their towers
+func (g *Game) drawBuildQueue(screen *ebiten.Image) {
+	for _, qb := range g.BuildQueue {
+		c := game.TowerTypes[qb.Kind].Color
+		c.A = GhostAlpha
+		px := float32(qb.Cell.X * game.CellSize)
+		py := float32(qb.Cell.Y * game.CellSize)
+		vector.DrawFilledRect(screen, px, py, game.CellSize, game.CellSize, c, false)
+	}
+}
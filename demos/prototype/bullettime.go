@@ -0,0 +1,49 @@
+package main
+
+import "github.com/toejough/claude-td/demos/prototype/game"
+
+const (
+	BulletTimeScale  = 0.25         // Simulation speed at the height of bullet time
+	BulletTimeFrames = 90           // Frames bullet time lasts, ramp included
+	BulletTimeRamp   = game.TPS / 2 // Final frames over which speed eases back to normal
+)
+
+// startBulletTime slows the game down for a moment, if the player allows it
+func (g *Game) startBulletTime() {
+	if g.settings.BulletTime {
+		g.bulletTime = BulletTimeFrames
+	}
+}
+
+// timeScale returns how many simulation steps run per frame right now:
+// BulletTimeScale while bullet time holds, easing back up to 1 at its end
+func (g *Game) timeScale() float64 {
+	if g.bulletTime <= 0 {
+		return 1
+	}
+	if g.bulletTime > BulletTimeRamp {
+		return BulletTimeScale
+	}
+	eased := 1 - float64(g.bulletTime)/BulletTimeRamp
+	return BulletTimeScale + (1-BulletTimeScale)*eased
+}
+
+// stepsThisFrame returns how many simulation steps to run this frame. Every
+// step is still a whole tick, so slow motion stays deterministic: it just
+// runs ticks on fewer frames, carrying the fraction over. While frozen,
+// ticks only run on request, and in the map editor not at all.
+func (g *Game) stepsThisFrame() int {
+	if g.editing {
+		return 0
+	}
+	if g.frozen {
+		return g.frozenSteps()
+	}
+	g.stepCarry += g.timeScale() * float64(g.gameSpeed())
+	if g.bulletTime > 0 {
+		g.bulletTime--
+	}
+	n := int(g.stepCarry)
+	g.stepCarry -= float64(n)
+	return n
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var moundColor = color.RGBA{R: 60, G: 45, B: 30, A: 170}
+
+// drawMound draws a burrowed enemy as a faint mound of earth
+func drawMound(screen *ebiten.Image, e *game.Enemy) {
+	x, y := float32(e.X), float32(e.Y)
+	vector.DrawFilledRect(screen, x-game.EnemyRadius, y, game.EnemyRadius*2, game.EnemyRadius/2, moundColor, true)
+	vector.DrawFilledCircle(screen, x, y, game.EnemyRadius*2/3, moundColor, true)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	MinZoom  = 1.0  // Whole grid fills the window
+	MaxZoom  = 3.0  // Closest zoom
+	ZoomStep = 1.25 // Zoom factor per wheel notch
+	PanSpeed = 8.0  // Screen pixels the view moves per tick while an arrow key is held
+
+	DragThreshold = 6.0 // Screen pixels the right button must move to pan rather than click
+)
+
+// Camera maps world pixels (the full grid) to screen pixels (the window)
+type Camera struct {
+	X, Y float64 // World position shown at the screen's top-left corner
+	Zoom float64 // Screen pixels per world pixel
+	W, H float64 // World size in pixels, which the screen shows at MinZoom
+}
+
+// PanDrag tracks a right button press, which either drags the view or,
+// released without moving, counts as a click
+type PanDrag struct {
+	Active         bool // The right button is down
+	Dragging       bool // It moved past DragThreshold, so it's a pan
+	StartX, StartY int  // Screen position it was pressed at
+	LastX, LastY   int  // Screen position last tick
+}
+
+// newCamera returns a camera showing the whole of a world of the given size
+func newCamera(width, height int) Camera {
+	return Camera{Zoom: MinZoom, W: float64(width), H: float64(height)}
+}
+
+// toWorld converts a screen position to world pixels
+func (c Camera) toWorld(sx, sy int) (float64, float64) {
+	return c.X + float64(sx)/c.Zoom, c.Y + float64(sy)/c.Zoom
+}
+
+// toScreen converts a world position to screen pixels
+func (c Camera) toScreen(wx, wy float64) (float64, float64) {
+	return (wx - c.X) * c.Zoom, (wy - c.Y) * c.Zoom
+}
+
+// viewSize returns how much of the world the screen shows, in world pixels
+func (c Camera) viewSize() (float64, float64) {
+	return c.W / c.Zoom, c.H / c.Zoom
+}
+
+// geoM returns the transform for drawing the world image to the screen
+func (c Camera) geoM() ebiten.GeoM {
+	var m ebiten.GeoM
+	m.Translate(-c.X, -c.Y)
+	m.Scale(c.Zoom, c.Zoom)
+	return m
+}
+
+// clamp keeps the view inside the world
+func (c *Camera) clamp() {
+	c.Zoom = math.Max(MinZoom, math.Min(MaxZoom, c.Zoom))
+	w, h := c.viewSize()
+	c.X = math.Max(0, math.Min(c.W-w, c.X))
+	c.Y = math.Max(0, math.Min(c.H-h, c.Y))
+}
+
+// zoomAt zooms by factor, keeping the world point under the screen position fixed
+func (c *Camera) zoomAt(sx, sy int, factor float64) {
+	wx, wy := c.toWorld(sx, sy)
+	c.Zoom *= factor
+	c.X = wx - float64(sx)/c.Zoom
+	c.Y = wy - float64(sy)/c.Zoom
+	c.clamp()
+}
+
+// centerOn moves the view so a world position is in the middle of the screen
+func (c *Camera) centerOn(wx, wy float64) {
+	w, h := c.viewSize()
+	c.X, c.Y = wx-w/2, wy-h/2
+	c.clamp()
+}
+
+// zoomedIn returns true if part of the world is off screen
+func (c Camera) zoomedIn() bool {
+	return c.Zoom > MinZoom
+}
+
+// handleCameraInput pans with the arrow keys and zooms with the mouse wheel
+// (unless the wheel was used to rotate towers)
+func (g *Game) handleCameraInput(mx, my int, wheelUsed bool) {
+	step := PanSpeed / g.camera.Zoom
+	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+		g.camera.X -= step
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+		g.camera.X += step
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+		g.camera.Y -= step
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+		g.camera.Y += step
+	}
+	g.camera.clamp()
+
+	if _, wheel := ebiten.Wheel(); wheel != 0 && !wheelUsed {
+		factor := ZoomStep
+		if wheel < 0 {
+			factor = 1 / ZoomStep
+		}
+		g.camera.zoomAt(mx, my, factor)
+	}
+}
+
+// handlePanDrag pans the view while the right button is dragged. Returns
+// true on the tick a right click (a press released without dragging) ends.
+func (g *Game) handlePanDrag(sx, sy int) bool {
+	d := &g.panDrag
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		*d = PanDrag{Active: true, StartX: sx, StartY: sy, LastX: sx, LastY: sy}
+	}
+	if !d.Active {
+		return false
+	}
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
+		if !d.Dragging && math.Hypot(float64(sx-d.StartX), float64(sy-d.StartY)) > DragThreshold {
+			d.Dragging = true
+		}
+		if d.Dragging {
+			g.camera.X -= float64(sx-d.LastX) / g.camera.Zoom
+			g.camera.Y -= float64(sy-d.LastY) / g.camera.Zoom
+			g.camera.clamp()
+		}
+		d.LastX, d.LastY = sx, sy
+		return false
+	}
+	d.Active = false
+	return !d.Dragging
+}
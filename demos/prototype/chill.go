@@ -0,0 +1,19 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var chillColor = color.RGBA{R: 80, G: 160, B: 255, A: 255}
+
+// chillTint returns an enemy's body color, tinted blue while it's chilled
+func chillTint(e *game.Enemy) color.RGBA {
+	c := game.EnemyTypes[e.Kind].Color
+	if e.SlowTTL == 0 {
+		return c
+	}
+	mix := func(a, b uint8) uint8 { return uint8((int(a) + int(b)) / 2) }
+	return color.RGBA{R: mix(c.R, chillColor.R), G: mix(c.G, chillColor.G), B: mix(c.B, chillColor.B), A: c.A}
+}
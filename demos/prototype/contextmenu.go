@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// ContextItem is one action in a tower's context menu
+type ContextItem struct {
+	Label  string
+	Action func(g *Game, t *game.Tower)
+}
+
+// ContextMenu is the action list opened by right-clicking a tower
+type ContextMenu struct {
+	Tower *game.Tower
+	X, Y  int // Screen position it was opened at
+	Items []ContextItem
+}
+
+// towerActions lists what can be done to a tower from its context menu
+func (g *Game) towerActions(t *game.Tower) []ContextItem {
+	items := []ContextItem{{
+		Label: fmt.Sprintf("Sell (+%d)", g.SellValue(t)),
+		Action: func(g *Game, t *game.Tower) {
+			g.RemoveTower(t.X, t.Y)
+			g.OnGridChanged(nil, true)
+		},
+	}, {
+		Label:  fmt.Sprintf("Move (-%d)", game.MoveFee),
+		Action: (*Game).startMove,
+	}}
+	if t.Level < game.MaxTowerLevel {
+		items = append(items, ContextItem{
+			Label:  fmt.Sprintf("Upgrade to level %d (-%d)", t.Level+1, game.UpgradeCost(t)),
+			Action: func(g *Game, t *game.Tower) { g.UpgradeTower(t.X, t.Y) },
+		})
+	}
+	if canFireManually(t.Kind) {
+		items = append(items, ContextItem{
+			Label: fmt.Sprintf("Target: %s (next: %s)", t.TargetMode, t.TargetMode.Next()),
+			Action: func(g *Game, t *game.Tower) {
+				t.TargetMode = t.TargetMode.Next()
+				t.Target = nil
+			},
+		})
+		mode := "Manual fire"
+		if t.ManualFire {
+			mode = "Auto fire"
+		}
+		items = append(items, ContextItem{
+			Label: "Switch to " + mode,
+			Action: func(g *Game, t *game.Tower) {
+				t.ManualFire = !t.ManualFire
+				t.FireQueued = false
+			},
+		})
+	}
+	return items
+}
+
+// openContextMenu shows a tower's actions at a screen position
+func (g *Game) openContextMenu(t *game.Tower, sx, sy int) {
+	g.contextMenu = &ContextMenu{Tower: t, X: sx, Y: sy, Items: g.towerActions(t)}
+}
+
+// contextMenuRect returns the open context menu's screen rectangle, shifted
+// to stay on screen
+func (g *Game) contextMenuRect() (x, y, w, h int) {
+	cm := g.contextMenu
+	for _, it := range cm.Items {
+		w = max(w, g.textWidth(it.Label))
+	}
+	w += 8
+	h = len(cm.Items)*g.lineHeight() + 4
+	return min(cm.X, g.screenWidth()-w), min(cm.Y, g.screenHeight()-h), w, h
+}
+
+// handleContextMenuInput runs the clicked context menu action. Any click
+// (or Esc) closes the menu; a click outside it does nothing else. Returns
+// true if the input was used, so it doesn't also reach the board.
+func (g *Game) handleContextMenuInput(sx, sy int) bool {
+	cm := g.contextMenu
+	if cm == nil {
+		return false
+	}
+	if !slices.Contains(g.Towers, cm.Tower) { // Sold or destroyed meanwhile
+		g.contextMenu = nil
+		return false
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.contextMenu = nil
+		return true
+	}
+	x, y, w, h := g.contextMenuRect()
+	inside := sx >= x && sx < x+w && sy >= y+2 && sy < y+h-2
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return inside
+	}
+	g.contextMenu = nil
+	if inside {
+		cm.Items[(sy-y-2)/g.lineHeight()].Action(g, cm.Tower)
+	}
+	return true
+}
+
+// drawContextMenu draws the open context menu, highlighting the hovered item
+func (g *Game) drawContextMenu(screen *ebiten.Image) {
+	if g.contextMenu == nil {
+		return
+	}
+	x, y, w, h := g.contextMenuRect()
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), menuPanelColor, false)
+	sx, sy := ebiten.CursorPosition()
+	lh := g.lineHeight()
+	for i, it := range g.contextMenu.Items {
+		iy := y + 2 + i*lh
+		if sx >= x && sx < x+w && sy >= iy && sy < iy+lh {
+			vector.DrawFilledRect(screen, float32(x), float32(iy), float32(w), float32(lh), menuCursorColor, false)
+		}
+		g.drawText(screen, it.Label, x+4, iy)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// In the map editor the simulation holds still and clicks reshape the board
+// instead of building: left paints walls, a right click clears back to
+// ground, Shift+click moves the nearest spawn and Ctrl+click the nearest
+// base. The path is recomputed after every change, so a map with no way
+// through shows at once.
+
+// handleEditInput applies the editor's click to the hovered cell
+func (g *Game) handleEditInput(rightClick bool) {
+	if !g.hoverValid {
+		return
+	}
+	p := game.Point{X: g.hoverX, Y: g.hoverY}
+	left := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	switch {
+	case left && ebiten.IsKeyPressed(ebiten.KeyShift):
+		g.moveEndpoint(game.NearestPoint(g.Spawns, p), p, game.TileSpawn)
+	case left && ebiten.IsKeyPressed(ebiten.KeyControl):
+		g.moveEndpoint(game.NearestPoint(g.Bases, p), p, game.TileBase)
+	case left:
+		g.paintTile(p, game.TileWall)
+	case rightClick:
+		g.paintTile(p, game.TileGround)
+	}
+}
+
+// paintTile sets a cell to wall or ground. The spawn, base, towers, and void
+// are left alone.
+func (g *Game) paintTile(p game.Point, tile game.TileType) {
+	old := g.Grid[p.Y][p.X]
+	if old == tile || old == game.TileSpawn || old == game.TileBase || old == game.TileTower || old == game.TileVoid {
+		return
+	}
+	g.Grid[p.Y][p.X] = tile
+	g.OnGridChanged(nil, true)
+}
+
+// moveEndpoint moves a spawn or base (whichever end points at) to a cell,
+// leaving ground where it was. It can't land on another spawn or base, a
+// tower, or void.
+func (g *Game) moveEndpoint(end *game.Point, p game.Point, tile game.TileType) {
+	switch g.Grid[p.Y][p.X] {
+	case game.TileSpawn, game.TileBase, game.TileTower, game.TileVoid:
+		return
+	}
+	g.Grid[end.Y][end.X] = game.TileGround
+	g.Grid[p.Y][p.X] = tile
+	*end = p
+	g.OnGridChanged(nil, true)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var shieldColor = color.RGBA{R: 80, G: 160, B: 255, A: 255}
+
+var shredTint = color.RGBA{R: 0, G: 0, B: 0, A: 110}
+
+var crackColor = color.RGBA{R: 30, G: 20, B: 10, A: 255}
+
+// drawShred dims a shredded enemy and scratches cracks across it
+func drawShred(screen *ebiten.Image, e *game.Enemy) {
+	if e.Armor >= game.EnemyTypes[e.Kind].Armor {
+		return
+	}
+	x, y := float32(e.X), float32(e.Y)
+	vector.DrawFilledCircle(screen, x, y, float32(e.Radius()), shredTint, true)
+	vector.StrokeLine(screen, x-6, y-8, x+1, y, 1.5, crackColor, true)
+	vector.StrokeLine(screen, x+1, y, x-2, y+8, 1.5, crackColor, true)
+	vector.StrokeLine(screen, x+1, y, x+8, y-3, 1.5, crackColor, true)
+}
+
+// drawEnemyCounts draws a colored icon per enemy kind with its alive and
+// still-to-spawn counts, in the top-right corner starting at y top.
+// Returns the y just below the last row.
+func (g *Game) drawEnemyCounts(screen *ebiten.Image, top int) int {
+	alive, queued := g.EnemyCounts()
+	row := 0
+	for k, et := range game.EnemyTypes {
+		if alive[k] == 0 && queued[k] == 0 {
+			continue
+		}
+		text := fmt.Sprintf("%d alive, %d to come", alive[k], queued[k])
+		x := g.screenWidth() - g.textWidth(text) - 24
+		y := top + row*g.lineHeight()
+		vector.DrawFilledCircle(screen, float32(x+8), float32(y+g.lineHeight()/2), 5, et.Color, true)
+		g.drawText(screen, text, x+18, y)
+		row++
+	}
+	return top + row*g.lineHeight()
+}
+
+// drawSpawnTelegraph pulses the next lane's spawn tile in the incoming
+// enemy's color just before it appears
+func (g *Game) drawSpawnTelegraph(screen *ebiten.Image) {
+	ticks := g.TicksToNextSpawn()
+	if ticks < 0 || ticks >= game.SpawnTelegraphTicks || g.PathBlocked {
+		return
+	}
+	c := game.EnemyTypes[g.WaveEnemyKind(g.CurrentWave, g.WaveSpawned)].Color
+	pulse := 0.5 + 0.5*math.Sin(float64(ticks)*0.6)
+	c.A = uint8(80 + 140*pulse)
+	spawn := g.Spawns[g.NextLane%len(g.Spawns)]
+	px := float32(spawn.X * game.CellSize)
+	py := float32(spawn.Y * game.CellSize)
+	vector.StrokeRect(screen, px+2, py+2, game.CellSize-4, game.CellSize-4, 4, c, false)
+}
+
+// dangerColor maps a danger ratio from green (far) through yellow to red (close)
+func dangerColor(ratio float64) color.RGBA {
+	if ratio < 0.5 {
+		return color.RGBA{R: uint8(255 * ratio * 2), G: 255, A: 255}
+	}
+	return color.RGBA{R: 255, G: uint8(255 * (1 - ratio) * 2), A: 255}
+}
+
+// drawDangerRings rings every enemy in a color showing how close it is to the base
+func (g *Game) drawDangerRings(screen *ebiten.Image) {
+	if !g.showDanger {
+		return
+	}
+	for _, e := range g.Enemies {
+		c := dangerColor(g.DangerRatio(e))
+		vector.StrokeCircle(screen, float32(e.X), float32(e.Y), float32(e.Radius()+3), 2, c, true)
+	}
+}
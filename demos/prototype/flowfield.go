@@ -0,0 +1,39 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var flowColor = color.RGBA{R: 255, G: 255, B: 255, A: 110}
+
+// drawFlowField draws an arrow in each visible cell toward where an enemy
+// there would head next
+func (g *Game) drawFlowField(screen *ebiten.Image) {
+	if !g.showFlow {
+		return
+	}
+	w, h := g.camera.viewSize()
+	x0, y0 := int(g.camera.X)/game.CellSize, int(g.camera.Y)/game.CellSize
+	x1 := min(g.GridWidth()-1, int(g.camera.X+w)/game.CellSize)
+	y1 := min(g.GridHeight()-1, int(g.camera.Y+h)/game.CellSize)
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			next, ok := g.Flow[game.Point{X: x, Y: y}]
+			if !ok {
+				continue
+			}
+			dx, dy := float32(next.X-x), float32(next.Y-y)
+			cx, cy := game.CellCenter(x, y)
+			tipX, tipY := float32(cx)+dx*game.CellSize*0.3, float32(cy)+dy*game.CellSize*0.3
+			vector.StrokeLine(screen, float32(cx)-dx*game.CellSize*0.3, float32(cy)-dy*game.CellSize*0.3, tipX, tipY, 1, flowColor, true)
+			// Arrowhead: two barbs angled back from the tip
+			const barb = game.CellSize * 0.15
+			vector.StrokeLine(screen, tipX, tipY, tipX-(dx+dy)*barb, tipY-(dy-dx)*barb, 1, flowColor, true)
+			vector.StrokeLine(screen, tipX, tipY, tipX-(dx-dy)*barb, tipY-(dy+dx)*barb, 1, flowColor, true)
+		}
+	}
+}
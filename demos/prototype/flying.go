@@ -0,0 +1,37 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// drawFlyer draws a flying enemy as a triangle pointing where it's headed
+func drawFlyer(screen *ebiten.Image, e *game.Enemy, fill, outline color.RGBA) {
+	var dx, dy float64
+	if e.PathIndex < len(e.Path) {
+		tx, ty := game.CellCenter(e.Path[e.PathIndex].X, e.Path[e.PathIndex].Y)
+		dx, dy = game.Unit(tx-e.X, ty-e.Y)
+	}
+	if dx == 0 && dy == 0 {
+		dy = 1
+	}
+	r := float32(e.Radius())
+	x, y, fx, fy := float32(e.X), float32(e.Y), float32(dx), float32(dy)
+	var tri vector.Path
+	tri.MoveTo(x+fx*r, y+fy*r)                           // Nose
+	tri.LineTo(x-fx*r*0.6-fy*r*0.8, y-fy*r*0.6+fx*r*0.8) // Wing tips
+	tri.LineTo(x-fx*r*0.6+fy*r*0.8, y-fy*r*0.6-fx*r*0.8)
+	tri.Close()
+
+	op := &vector.DrawPathOptions{AntiAlias: true}
+	op.ColorScale.ScaleWithColor(fill)
+	vector.FillPath(screen, &tri, nil, op)
+	if outline.A > 0 {
+		op.ColorScale.Reset()
+		op.ColorScale.ScaleWithColor(outline)
+		vector.StrokePath(screen, &tri, &vector.StrokeOptions{Width: 2}, op)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var formationLinkColor = color.RGBA{R: 255, G: 255, B: 255, A: 60}
+
+// drawFormations draws a faint link between every pair of guarding enemies
+func (g *Game) drawFormations(screen *ebiten.Image) {
+	if !g.FormationsEnabled {
+		return
+	}
+	for i, a := range g.Enemies {
+		for _, b := range g.Enemies[i+1:] {
+			if game.FormationLinked(a, b) {
+				vector.StrokeLine(screen, float32(a.X), float32(a.Y), float32(b.X), float32(b.Y), 2, formationLinkColor, true)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// While frozen the game holds still except when asked to move: each press
+// of Period runs exactly one tick, so movement and targeting can be watched
+// frame by frame.
+
+// frozenSteps returns how many ticks to run this frame while frozen: one on
+// a press of Period, otherwise none
+func (g *Game) frozenSteps() int {
+	if inpututil.IsKeyJustPressed(ebiten.KeyPeriod) {
+		return 1
+	}
+	return 0
+}
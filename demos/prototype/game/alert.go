@@ -0,0 +1,35 @@
+package game
+
+const (
+	BaseAlertSteps     = 4  // Path steps from the base at which the warning starts
+	AlarmSlowestPeriod = 60 // Ticks between alarm beeps at the lowest alert level
+	AlarmFastestPeriod = 15 // Ticks between alarm beeps with an enemy at the base
+)
+
+// updateBaseAlert sets the alert level from the enemy closest to the base:
+// 0 when none is within BaseAlertSteps, rising to 1 as one reaches the base.
+// While alerted, the alarm beeps faster the higher the level.
+func (g *Game) updateBaseAlert() {
+	closest := BaseAlertSteps + 1
+	for _, e := range g.Enemies {
+		if e.HP > 0 {
+			closest = min(closest, e.stepsLeft())
+		}
+	}
+	g.BaseAlert = 1 - float64(closest)/float64(BaseAlertSteps+1)
+	if g.BaseAlert <= 0 {
+		g.BaseAlert = 0
+		g.alarmTimer = 0
+		return
+	}
+	g.waveNearLeak = true
+
+	if g.alarmTimer > 0 {
+		g.alarmTimer--
+		return
+	}
+	if g.AlarmEnabled {
+		g.Effects.Alarm()
+	}
+	g.alarmTimer = int(AlarmSlowestPeriod - g.BaseAlert*(AlarmSlowestPeriod-AlarmFastestPeriod))
+}
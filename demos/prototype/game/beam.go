@@ -0,0 +1,28 @@
+package game
+
+import "image/color"
+
+const BeamMaxWidth = 3 // Width multiplier of a fully charged beam
+
+// chargeBeam builds up a ramping tower's bonus for another hit on the same
+// target, up to its cap
+func (t *Tower) chargeBeam() {
+	tt := TowerTypes[t.Kind]
+	t.BeamCharge = min(t.BeamCharge+tt.Ramp, tt.MaxRamp)
+}
+
+// chargedBeam draws a ramping tower's shot thicker and whiter the more charge
+// it has built up
+func chargedBeam(l *Laser, t *Tower) {
+	tt := TowerTypes[t.Kind]
+	if tt.MaxRamp == 0 {
+		return
+	}
+	f := t.BeamCharge / tt.MaxRamp
+	l.Width *= float32(1 + f*(BeamMaxWidth-1))
+	if l.Color.A == 0 {
+		return // Palette colored; leave it be
+	}
+	lerp := func(c uint8) uint8 { return c + uint8(f*float64(255-c)) }
+	l.Color = color.RGBA{R: lerp(l.Color.R), G: lerp(l.Color.G), B: lerp(l.Color.B), A: l.Color.A}
+}
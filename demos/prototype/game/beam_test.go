@@ -0,0 +1,54 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFocusBeamChargeCapsAndResets(t *testing.T) {
+	g := newTestGame(t, openField...)
+	tower := buildTower(t, g, 5, 1, KindFocus)
+	first := placeEnemy(g, 5, 3)
+	second := placeEnemy(g, 10, 5) // Out of range for now
+	for _, e := range []*Enemy{first, second} {
+		e.HP = 1e6 // Survives every shot
+	}
+	tt := TowerTypes[KindFocus]
+	fire := func() {
+		tower.Cooldown = 0
+		g.updateTowers()
+	}
+
+	for hit := 1; hit <= 2*int(tt.MaxRamp/tt.Ramp); hit++ {
+		fire()
+		want := math.Min(float64(hit)*tt.Ramp, tt.MaxRamp)
+		if math.Abs(tower.BeamCharge-want) > 1e-9 {
+			t.Fatalf("charge after %d hits = %v, want %v", hit, tower.BeamCharge, want)
+		}
+	}
+	if tower.BeamCharge != tt.MaxRamp {
+		t.Fatalf("charge = %v, want the cap %v", tower.BeamCharge, tt.MaxRamp)
+	}
+
+	// Switching targets starts the charge over: the first hit is uncharged
+	first.X, first.Y = CellCenter(10, 5)
+	second.X, second.Y = CellCenter(5, 3)
+	hp := second.HP
+	fire()
+	if tower.Target != second {
+		t.Fatalf("tower didn't switch to the enemy in range")
+	}
+	if dealt := hp - second.HP; dealt != tt.Damage {
+		t.Errorf("first hit after switching dealt %v, want %v", dealt, tt.Damage)
+	}
+	if tower.BeamCharge != tt.Ramp {
+		t.Errorf("charge after switching = %v, want %v", tower.BeamCharge, tt.Ramp)
+	}
+
+	// Losing every target drops it too
+	second.X, second.Y = CellCenter(10, 4)
+	fire()
+	if tower.Target != nil || tower.BeamCharge != 0 {
+		t.Errorf("tower with nothing in range has target %v and charge %v", tower.Target != nil, tower.BeamCharge)
+	}
+}
@@ -0,0 +1,29 @@
+package game
+
+// The final wave of a normal game is a single boss: a huge, slow enemy that
+// has to be killed to win. Its kind's scale factors make it bigger, tougher,
+// and slower than the rest.
+
+// IsBossWave returns true if a wave is the boss wave: the last one, outside
+// endless mode
+func (g *Game) IsBossWave(wave int) bool {
+	return !g.Endless && wave == TotalWaves
+}
+
+// orOne returns a scale factor, treating unset (0) as no change
+func orOne(scale float64) float64 {
+	if scale == 0 {
+		return 1
+	}
+	return scale
+}
+
+// MaxHP returns the health an enemy of a kind spawns with, before jitter
+func MaxHP(kind EnemyKind) float64 {
+	return EnemyMaxHP * orOne(EnemyTypes[kind].HPScale)
+}
+
+// Radius returns how big an enemy is drawn
+func (e *Enemy) Radius() float64 {
+	return EnemyRadius * orOne(EnemyTypes[e.Kind].Size)
+}
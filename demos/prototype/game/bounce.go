@@ -0,0 +1,57 @@
+package game
+
+import "math"
+
+// BounceImmunity is how long a bounced enemy can't be bounced again. It's
+// several cells' worth of walking, so every bounce is followed by net
+// progress and pads can never trap an enemy in a loop.
+const BounceImmunity = 2 * TPS
+
+// bouncePad returns a ready bounce pad whose trigger zone covers a cell, or nil
+func (g *Game) bouncePad(c Point) *Tower {
+	px, py := CellCenter(c.X, c.Y)
+	for _, t := range g.Towers {
+		if !TowerTypes[t.Kind].Bounce || t.BuildTimer > 0 || t.Cooldown > 0 {
+			continue
+		}
+		tx, ty := CellCenter(t.X, t.Y)
+		if math.Hypot(px-tx, py-ty) <= g.EffectiveStats(t).Range {
+			return t
+		}
+	}
+	return nil
+}
+
+// bounceEnemy shoves an enemy that stepped into a pad's zone back to the
+// previous cell on its own path. That cell was walkable a moment ago, so the
+// enemy can't end up off the grid or inside a wall, and it resumes its route
+// heading back into the cell it was pushed out of.
+func (g *Game) bounceEnemy(e *Enemy) {
+	if e.bounceImmune > 0 {
+		e.bounceImmune--
+		return
+	}
+	if e.Burrowed > 0 || e.PathIndex >= len(e.Path) {
+		return // Tunnels right under the pads
+	}
+	cell := Point{X: int(e.X) / CellSize, Y: int(e.Y) / CellSize}
+	// The enemy is on its next waypoint's cell, or (past that cell's center,
+	// e.g. while rounding a corner) still on the previous one
+	i := e.PathIndex
+	if e.Path[i] != cell {
+		i--
+	}
+	if i < 1 || e.Path[i] != cell {
+		return
+	}
+	t := g.bouncePad(cell)
+	if t == nil {
+		return
+	}
+	back := e.Path[i-1]
+	e.X, e.Y = CellCenter(back.X, back.Y)
+	e.PathIndex = i
+	e.bounceImmune = BounceImmunity
+	t.Cooldown = g.EffectiveStats(t).Cooldown
+	g.AddFloatingText(e.X, e.Y, "BOUNCE")
+}
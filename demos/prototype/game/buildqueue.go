@@ -0,0 +1,63 @@
+package game
+
+// QueuedBuild is a tower placement waiting for the resources to pay for it
+type QueuedBuild struct {
+	Cell Point
+	Kind TowerKind
+}
+
+// queuedAt returns the index of the queued placement on a cell, or -1
+func (g *Game) queuedAt(x, y int) int {
+	for i, qb := range g.BuildQueue {
+		if qb.Cell == (Point{X: x, Y: y}) {
+			return i
+		}
+	}
+	return -1
+}
+
+// queueBuild adds a placement to the end of the build queue, unless the
+// cell already has one queued
+func (g *Game) queueBuild(x, y int, kind TowerKind) {
+	if g.queuedAt(x, y) >= 0 {
+		return
+	}
+	g.BuildQueue = append(g.BuildQueue, QueuedBuild{Cell: Point{X: x, Y: y}, Kind: kind})
+}
+
+// cancelBuild removes the queued placement on a cell. Returns false if
+// there wasn't one.
+func (g *Game) cancelBuild(x, y int) bool {
+	i := g.queuedAt(x, y)
+	if i < 0 {
+		return false
+	}
+	g.BuildQueue = append(g.BuildQueue[:i], g.BuildQueue[i+1:]...)
+	return true
+}
+
+// updateBuildQueue builds the next queued tower once it can be paid for.
+// Placements are built strictly in order; one whose cell was taken in the
+// meantime is dropped.
+func (g *Game) updateBuildQueue() {
+	for len(g.BuildQueue) > 0 {
+		next := g.BuildQueue[0]
+		if g.Grid[next.Cell.Y][next.Cell.X] == TileGround {
+			break
+		}
+		g.BuildQueue = g.BuildQueue[1:]
+	}
+	if len(g.BuildQueue) == 0 {
+		return
+	}
+	next := g.BuildQueue[0]
+	if g.MaxTowers > 0 && len(g.Towers) >= g.MaxTowers {
+		return // Wait for a free slot rather than repeating the limit message
+	}
+	if !g.addTower(next.Cell.X, next.Cell.Y, next.Kind) {
+		return
+	}
+	g.BuildQueue = g.BuildQueue[1:]
+	g.OnGridChanged([]Point{next.Cell}, false)
+	g.rewardMazing(next.Cell.X, next.Cell.Y)
+}
@@ -0,0 +1,41 @@
+package game
+
+const (
+	BurrowDuration  = 3 * TPS // Ticks a burrower stays underground
+	SurfaceDuration = 4 * TPS // Ticks a burrower stays up between burrows
+	BurrowBaseSteps = 3       // Burrowers surface this many path steps from the base, and can't dig in closer
+)
+
+// updateEnemyAbilities runs an enemy's per-tick abilities and effects:
+// shield regen, armor recovery, chill wearing off, and burrowing
+func (g *Game) updateEnemyAbilities(e *Enemy) {
+	e.regenShield()
+	e.recoverArmor()
+	e.updateChill()
+	if EnemyTypes[e.Kind].Burrows {
+		e.updateBurrow()
+	}
+}
+
+// updateBurrow alternates a burrower between the surface and underground.
+// Near the base it's forced up, so it can only leak where towers can hit it.
+func (e *Enemy) updateBurrow() {
+	nearBase := e.stepsLeft() <= BurrowBaseSteps
+	if e.Burrowed > 0 {
+		e.Burrowed--
+		if nearBase {
+			e.Burrowed = 0
+		}
+		if e.Burrowed == 0 {
+			e.surfaceTimer = SurfaceDuration
+		}
+		return
+	}
+	if e.surfaceTimer > 0 {
+		e.surfaceTimer--
+		return
+	}
+	if !nearBase {
+		e.Burrowed = BurrowDuration
+	}
+}
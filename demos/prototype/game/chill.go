@@ -0,0 +1,19 @@
+package game
+
+// chill slows an enemy by a fraction of its speed for a number of ticks.
+// Chills don't stack: the stronger slow and the longer duration win.
+func (e *Enemy) chill(factor float64, ticks int) {
+	e.SlowFactor = max(e.SlowFactor, factor)
+	e.SlowTTL = max(e.SlowTTL, ticks)
+}
+
+// updateChill counts down an enemy's chill, lifting it when it runs out
+func (e *Enemy) updateChill() {
+	if e.SlowTTL == 0 {
+		return
+	}
+	e.SlowTTL--
+	if e.SlowTTL == 0 {
+		e.SlowFactor = 0
+	}
+}
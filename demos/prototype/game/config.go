@@ -0,0 +1,15 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ConfigPath returns the path of a file in the game's config directory
+func ConfigPath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "claude-td", name), nil
+}
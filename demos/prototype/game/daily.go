@@ -0,0 +1,129 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"io/fs"
+	"log"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	ScorePerKill  = 10   // Score for each enemy killed
+	ScorePerWave  = 100  // Score for each wave cleared
+	ScoreWinBonus = 1000 // Score for surviving every wave
+
+	DailyMaxWaveBonus = 3 // Daily waves get up to this many extra enemies
+)
+
+// NewDailyChallenge creates the challenge for a date. Everyone playing the
+// same day gets the same seed, so scores are comparable.
+func NewDailyChallenge(date time.Time) *Game {
+	seed := dailySeed(date)
+	g := newGameFromMap(GenerateMap(int64(seed)))
+	g.Daily = true
+	g.DailyDate = date
+	g.Seed = seed
+	g.EnemiesThisWave = g.WaveSize(1)
+
+	best, err := loadDailyBests()
+	if err != nil {
+		log.Printf("loading daily bests: %v", err)
+	}
+	g.DailyBest = best[dailyKey(date)]
+	return g
+}
+
+// dailyKey identifies a challenge day, independent of time zone offsets
+func dailyKey(date time.Time) string {
+	return date.Format(time.DateOnly)
+}
+
+// dailySeed derives a challenge seed from a date
+func dailySeed(date time.Time) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(dailyKey(date)))
+	return h.Sum64()
+}
+
+// dailyWaveBonus returns the extra enemies a challenge wave gets. It seeds a
+// fresh generator per wave so any wave can be computed (and previewed) alone.
+func dailyWaveBonus(seed uint64, wave int) int {
+	rng := rand.New(rand.NewPCG(seed, uint64(wave)))
+	return rng.IntN(DailyMaxWaveBonus + 1)
+}
+
+// InSetupPhase returns true before the first enemy of the game has spawned
+func (g *Game) InSetupPhase() bool {
+	return g.CurrentWave == 1 && g.WaveSpawned == 0
+}
+
+// Score rates a run by kills and waves cleared
+func (g *Game) Score() int {
+	cleared := g.CurrentWave - 1
+	s := g.TotalKills * ScorePerKill
+	if g.State == StateWon {
+		cleared = g.CurrentWave
+		s += ScoreWinBonus
+	}
+	return s + cleared*ScorePerWave
+}
+
+// finishIfOver does the one-time bookkeeping when the game has just ended
+func (g *Game) finishIfOver() {
+	if g.State == StatePlaying || g.finished {
+		return
+	}
+	g.finished = true
+	g.LastLayout = g.towerLayout()
+	if g.Daily && g.Score() > g.DailyBest {
+		if err := saveDailyBest(g.DailyDate, g.Score()); err != nil {
+			log.Printf("saving daily best: %v", err)
+		}
+	}
+}
+
+// loadDailyBests reads the best score recorded for each challenge day
+func loadDailyBests() (map[string]int, error) {
+	best := make(map[string]int)
+	path, err := ConfigPath("daily.json")
+	if err != nil {
+		return best, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return best, nil
+	}
+	if err != nil {
+		return best, err
+	}
+	if err := json.Unmarshal(data, &best); err != nil {
+		return make(map[string]int), err
+	}
+	return best, nil
+}
+
+// saveDailyBest records a new best score for a challenge day
+func saveDailyBest(date time.Time, score int) error {
+	best, err := loadDailyBests()
+	if err != nil {
+		return err
+	}
+	best[dailyKey(date)] = score
+	path, err := ConfigPath("daily.json")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(best, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"slices"
+)
+
+// Gameplay must be a pure function of the starting game and the inputs, so
+// replays and seeded challenges play out the same every time. Rules that hold
+// this up:
+//   - Towers, enemies, traps, and lasers live in slices and are updated in
+//     slice order. Maps (g.selected, A*'s gScore) are only ever looked up,
+//     never ranged over where order could change an outcome.
+//   - A* breaks ties between equally good cells by insertion order.
+//   - Randomness comes from explicitly seeded generators; the sound board
+//     has its own so audio can't disturb gameplay.
+
+// StateDigest hashes the simulation's state: the counters, the grid and lane
+// paths, the build queue, and every field of the enemies (routes included),
+// towers, shells, traps, lasers, and pickups on the board, with pointers
+// between them hashed as slice indexes. Settings, which don't change in
+// play, and display-only text and explosions are left out. Two runs of the
+// same scenario with the same inputs must produce the same digest on every
+// tick.
+func (g *Game) StateDigest() uint64 {
+	h := fnv.New64a()
+	put := func(vals ...float64) {
+		var buf [8]byte
+		for _, v := range vals {
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+			h.Write(buf[:])
+		}
+	}
+	putBool := func(vals ...bool) {
+		for _, v := range vals {
+			if v {
+				put(1)
+			} else {
+				put(0)
+			}
+		}
+	}
+	putPath := func(path []Point) {
+		put(float64(len(path)))
+		for _, p := range path {
+			put(float64(p.X), float64(p.Y))
+		}
+	}
+	enemy := func(e *Enemy) float64 { return float64(slices.Index(g.Enemies, e)) }
+	tower := func(t *Tower) float64 { return float64(slices.Index(g.Towers, t)) }
+
+	put(float64(g.Tick), float64(g.State), float64(g.Resources), float64(g.spawnTimer), float64(g.SpawnStall))
+	put(float64(g.CurrentWave), float64(g.clearedWave), float64(g.EnemiesThisWave), float64(g.WaveSpawned), float64(g.WaveDelay), float64(g.NextLane))
+	put(float64(g.TotalKills), float64(g.totalSpawned), float64(g.WaveKills), float64(g.ZoneCooldown), g.overkillCarry)
+	put(float64(g.surgeKills), g.surgeProgress, float64(g.WaveSurges))
+	putBool(g.waveNearLeak, g.MegaWave)
+	put(float64(g.Routing), float64(g.nextBase))
+	put(g.BaseHP...)
+	for y := range g.Grid {
+		for x := range g.Grid[y] {
+			put(float64(g.Grid[y][x]))
+		}
+	}
+	for _, path := range g.Paths {
+		putPath(path)
+	}
+	for _, qb := range g.BuildQueue {
+		put(float64(qb.Cell.X), float64(qb.Cell.Y), float64(qb.Kind))
+	}
+	put(float64(len(g.Enemies)), float64(len(g.Towers)), float64(len(g.Projectiles)), float64(len(g.Traps)), float64(len(g.Lasers)), float64(len(g.Pickups)))
+	for _, e := range g.Enemies {
+		put(float64(e.Kind), e.X, e.Y, float64(e.PathIndex), e.HP, e.Shield, e.ShieldMax, float64(e.Reward), e.Armor)
+		put(float64(e.sinceHit), float64(e.shredTimer), e.guard, float64(e.bounceImmune), float64(e.Burrowed), float64(e.surfaceTimer))
+		put(float64(e.Stunned), float64(e.stunImmune), e.SlowFactor, float64(e.SlowTTL), e.incoming)
+		putPath(e.Path)
+	}
+	for _, t := range g.Towers {
+		put(float64(t.X), float64(t.Y), float64(t.Kind), float64(t.Cooldown), t.Facing, float64(t.BuildTimer), t.HP, enemy(t.Target))
+		put(float64(t.ShotsRemaining), float64(t.Kills), float64(t.KillStacks), float64(t.Level), float64(t.Invested))
+		put(t.BeamCharge, float64(t.TargetMode))
+		putBool(t.ManualFire, t.FireQueued)
+	}
+	for _, p := range g.Projectiles {
+		put(p.X, p.Y, p.VX, p.VY, p.Damage, enemy(p.target), tower(p.Tower))
+	}
+	for _, tr := range g.Traps {
+		put(float64(tr.Cell.X), float64(tr.Cell.Y), tr.Damage, float64(tr.TTL), tower(tr.Owner))
+	}
+	for _, l := range g.Lasers {
+		put(l.FromX, l.FromY, l.ToX, l.ToY, float64(l.TTL), float64(l.Trail))
+	}
+	if z := g.KillZone; z != nil {
+		put(z.X, z.Y, z.Radius, float64(z.TTL))
+	}
+	for _, p := range g.Pickups {
+		put(p.X, p.Y, float64(p.Value), float64(p.TTL))
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,125 @@
+package game
+
+import "testing"
+
+// scriptTicks is how long a scripted run plays: through the first waves
+const scriptTicks = 90 * TPS
+
+// script is a player's inputs by tick: towers of assorted kinds, some of
+// them on the route so enemies reroute, and a sale once the waves are on
+var script = map[int]Input{
+	0:        {Cell: Point{X: 9, Y: 4}, OnBoard: true, Build: true, Kind: KindBasic},
+	1:        {Cell: Point{X: 10, Y: 6}, OnBoard: true, Build: true, Kind: KindCannon},
+	2:        {Cell: Point{X: 11, Y: 8}, OnBoard: true, Build: true, Kind: KindSplash},
+	3:        {Cell: Point{X: 9, Y: 10}, OnBoard: true, Build: true, Kind: KindFrost},
+	4:        {Cell: Point{X: 10, Y: 11}, OnBoard: true, Build: true, Kind: KindRapid},
+	5:        {Cell: Point{X: 12, Y: 5}, OnBoard: true, Build: true, Kind: KindSniper},
+	30 * TPS: {Cell: Point{X: 10, Y: 6}, OnBoard: true, Sell: true},
+	31 * TPS: {Cell: Point{X: 10, Y: 7}, OnBoard: true, Build: true, Kind: KindFocus},
+}
+
+func init() {
+	for y := 3; y <= 12; y++ { // Walls of towers either side of the route
+		script[100+y] = Input{Cell: Point{X: 7, Y: y}, OnBoard: true, Build: true, Kind: KindRapid}
+		script[200+y] = Input{Cell: Point{X: 13, Y: y}, OnBoard: true, Build: true, Kind: KindBasic}
+	}
+}
+
+// playScript steps a game through the script with most opt-in rules on,
+// returning the digest after every step
+func playScript(g *Game) []uint64 {
+	g.Resources = 10000
+	g.SmoothPaths, g.Separation, g.SkipDoomed = true, true, true
+	g.FormationsEnabled, g.PickupsEnabled, g.RageEnabled = true, true, true
+	digests := make([]uint64, scriptTicks)
+	for tick := range scriptTicks {
+		g.Step(script[tick])
+		digests[tick] = g.StateDigest()
+	}
+	return digests
+}
+
+// checkSameDigests fails the test at the first tick two runs part ways
+func checkSameDigests(t *testing.T, a, b []uint64) {
+	t.Helper()
+	for tick := range a {
+		if a[tick] != b[tick] {
+			t.Fatalf("runs diverged at tick %d", tick)
+		}
+	}
+}
+
+func TestSameScenarioReplaysIdentically(t *testing.T) {
+	a := playScript(NewGame(GridWidth, GridHeight))
+	b := playScript(NewGame(GridWidth, GridHeight))
+	checkSameDigests(t, a, b)
+}
+
+func TestSameSeedReplaysIdentically(t *testing.T) {
+	a := playScript(NewGameSeed(42))
+	b := playScript(NewGameSeed(42))
+	checkSameDigests(t, a, b)
+
+	if c := playScript(NewGameSeed(43)); c[len(c)-1] == a[len(a)-1] {
+		t.Error("another seed played out the same")
+	}
+}
+
+func TestSpawnHPStaysWithinJitter(t *testing.T) {
+	g := NewGameSeed(7)
+	for range 200 {
+		g.spawnEnemy(EnemyNormal)
+	}
+	full := MaxHP(EnemyNormal)
+	varied := false
+	for _, e := range g.Enemies {
+		if e.HP < full*(1-EnemyHPJitter) || e.HP > full*(1+EnemyHPJitter) {
+			t.Fatalf("spawned with %v HP, want within %v of %v", e.HP, EnemyHPJitter, full)
+		}
+		varied = varied || e.HP != g.Enemies[0].HP
+	}
+	if !varied {
+		t.Error("every enemy spawned with the same HP")
+	}
+}
+
+func TestDigestChangesWithEachField(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		change func(g *Game, e *Enemy, tw *Tower)
+	}{
+		{"enemy route", func(g *Game, e *Enemy, tw *Tower) { e.Path = e.Path[:len(e.Path)-1] }},
+		{"burrowed", func(g *Game, e *Enemy, tw *Tower) { e.Burrowed = 1 }},
+		{"surface timer", func(g *Game, e *Enemy, tw *Tower) { e.surfaceTimer = 1 }},
+		{"chill", func(g *Game, e *Enemy, tw *Tower) { e.SlowFactor = 0.5 }},
+		{"stun immunity", func(g *Game, e *Enemy, tw *Tower) { e.stunImmune = 1 }},
+		{"shred timer", func(g *Game, e *Enemy, tw *Tower) { e.shredTimer = 1 }},
+		{"bounce immunity", func(g *Game, e *Enemy, tw *Tower) { e.bounceImmune = 1 }},
+		{"incoming damage", func(g *Game, e *Enemy, tw *Tower) { e.incoming = 1 }},
+		{"guard", func(g *Game, e *Enemy, tw *Tower) { e.guard = 0.5 }},
+		{"shots left", func(g *Game, e *Enemy, tw *Tower) { tw.ShotsRemaining++ }},
+		{"kill stacks", func(g *Game, e *Enemy, tw *Tower) { tw.KillStacks++ }},
+		{"invested", func(g *Game, e *Enemy, tw *Tower) { tw.Invested++ }},
+		{"target", func(g *Game, e *Enemy, tw *Tower) { tw.Target = e }},
+		{"manual fire", func(g *Game, e *Enemy, tw *Tower) { tw.ManualFire = true }},
+		{"fire queued", func(g *Game, e *Enemy, tw *Tower) { tw.FireQueued = true }},
+		{"spawn count", func(g *Game, e *Enemy, tw *Tower) { g.totalSpawned++ }},
+		{"lane path", func(g *Game, e *Enemy, tw *Tower) { g.Paths[0] = nil }},
+		{"build queue", func(g *Game, e *Enemy, tw *Tower) { g.BuildQueue = []QueuedBuild{{Cell: Point{X: 5, Y: 5}}} }},
+		{"lasers", func(g *Game, e *Enemy, tw *Tower) { g.addLaser(KindBasic, 0, 0, 1, 1) }},
+		{"surge kills", func(g *Game, e *Enemy, tw *Tower) { g.surgeKills++ }},
+		{"surge progress", func(g *Game, e *Enemy, tw *Tower) { g.surgeProgress++ }},
+		{"cleared wave", func(g *Game, e *Enemy, tw *Tower) { g.clearedWave++ }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestGame(t, openField...)
+			tw := buildTower(t, g, 5, 1, KindBasic)
+			g.spawnEnemy(EnemyNormal)
+			before := g.StateDigest()
+			tc.change(g, g.Enemies[0], tw)
+			if g.StateDigest() == before {
+				t.Error("digest didn't change")
+			}
+		})
+	}
+}
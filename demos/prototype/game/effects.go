@@ -0,0 +1,19 @@
+package game
+
+// Effects are the cues the simulation gives whatever presents it: sounds to
+// play and moments worth slowing down for. The simulation never plays or
+// slows anything itself.
+type Effects interface {
+	Shot()       // A tower fired
+	Alarm()      // The base is under threat, or spawning has stalled
+	BossKilled() // A boss died
+}
+
+// noEffects ignores every cue, for games nothing presents
+type noEffects struct{}
+
+func (noEffects) Shot() {}
+
+func (noEffects) Alarm() {}
+
+func (noEffects) BossKilled() {}
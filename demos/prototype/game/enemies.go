@@ -0,0 +1,234 @@
+package game
+
+import (
+	"image/color"
+	"math"
+)
+
+// EnemyKind identifies a type of enemy
+type EnemyKind int
+
+const (
+	EnemyNormal   EnemyKind = iota // Walks the path, nothing special
+	EnemyShielded                  // Regenerating shield absorbs damage before HP
+	EnemyArmored                   // Armor blunts every hit
+	EnemyBurrower                  // Periodically tunnels underground, out of reach
+	EnemyFlying                    // Flies straight to the base over walls and towers
+	EnemyBoss                      // The final wave: one huge, slow, tough enemy
+	EnemyHealer                    // Heals other enemies around it
+)
+
+const (
+	ShieldRegenDelay  = 3 * TPS // Ticks without damage before a shield starts regenerating
+	ShieldRegenRate   = 0.5     // Shield points restored per tick while regenerating
+	ShieldedFirstWave = 3       // First wave that sends shielded enemies
+	ShieldedEvery     = 4       // Every Nth enemy of a wave is shielded, from then on
+	ArmoredFirstWave  = 4       // First wave that sends armored enemies
+	ArmoredEvery      = 5       // Every Nth enemy of a wave is armored, from then on
+	BurrowerFirstWave = 4       // First wave that sends burrowers
+	BurrowerEvery     = 7       // Every Nth enemy of a wave is a burrower, from then on
+	FlyingFirstWave   = 3       // First wave that sends flyers
+	FlyingEvery       = 6       // Every Nth enemy of a wave is a flyer, from then on
+	HealerFirstWave   = 4       // First wave that sends healers
+	HealerEvery       = 9       // Every Nth enemy of a wave is a healer, from then on
+
+	MinHitDamage  = 1.0     // Armor never reduces a hit below this
+	ShredDuration = 4 * TPS // Ticks shredded armor stays down after the last shred
+
+	SpawnTelegraphTicks = 30 // Spawn tile pulses for this long before an enemy appears
+)
+
+// EnemyType describes everything shared by enemies of one kind
+type EnemyType struct {
+	Name      string
+	Color     color.RGBA
+	ShieldMax float64 // Shield points on spawn; 0 for none
+	Armor     float64 // Flat reduction to every hit; 0 for none
+	Boss      bool    // Killing one sets off bullet time
+	Burrows   bool    // Spends stretches underground, untargetable
+	Flies     bool    // Ignores the maze, terrain, tar, and traps, heading straight for the base
+	HPScale   float64 // Multiplies spawn HP; 0 = normal
+	Size      float64 // Multiplies the drawn radius; 0 = normal
+	Speed     float64 // Multiplies walking speed; 0 = normal
+	Heals     bool    // Restores HP to other enemies within HealRadius
+
+	DropChance  float64 // Chance (0..1) of dropping a resource orb on death, with pickups on
+	RewardBonus int     // Added to KillReward for killing one
+
+	SprintSteps int     // Speeds up within this many steps of the base; 0 = never
+	SprintSpeed float64 // Speed multiplier while sprinting
+}
+
+// EnemyTypes holds each enemy kind's description, indexed by EnemyKind
+var EnemyTypes = []EnemyType{
+	EnemyNormal:   {Name: "Normal", Color: enemyColor, DropChance: 0.1},
+	EnemyShielded: {Name: "Shielded", Color: color.RGBA{R: 150, G: 130, B: 255, A: 255}, ShieldMax: 60, DropChance: 0.2},
+	EnemyArmored:  {Name: "Armored", Color: color.RGBA{R: 95, G: 95, B: 110, A: 255}, Armor: 8, DropChance: 0.2, RewardBonus: 5},
+	EnemyBurrower: {Name: "Burrower", Color: color.RGBA{R: 190, G: 140, B: 90, A: 255}, Burrows: true, DropChance: 0.25},
+	EnemyFlying:   {Name: "Flying", Color: color.RGBA{R: 240, G: 220, B: 120, A: 255}, Flies: true, DropChance: 0.2},
+	EnemyBoss:     {Name: "Boss", Color: color.RGBA{R: 130, G: 40, B: 150, A: 255}, Boss: true, HPScale: 20, Size: 1.8, Speed: 0.5, DropChance: 1, RewardBonus: 90},
+	EnemyHealer:   {Name: "Healer", Color: color.RGBA{R: 120, G: 230, B: 150, A: 255}, Heals: true, DropChance: 0.25},
+}
+
+// WaveEnemyKind returns the kind of the n-th enemy (0-based) spawned in a wave.
+// Depending only on its inputs and the game mode, it can preview upcoming
+// spawns exactly.
+func (g *Game) WaveEnemyKind(wave, n int) EnemyKind {
+	if g.IsBossWave(wave) {
+		return EnemyBoss
+	}
+	if wave >= ShieldedFirstWave && n%ShieldedEvery == ShieldedEvery-1 {
+		return EnemyShielded
+	}
+	if wave >= ArmoredFirstWave && n%ArmoredEvery == ArmoredEvery-1 {
+		return EnemyArmored
+	}
+	if wave >= BurrowerFirstWave && n%BurrowerEvery == BurrowerEvery-1 {
+		return EnemyBurrower
+	}
+	if wave >= FlyingFirstWave && n%FlyingEvery == FlyingEvery-1 {
+		return EnemyFlying
+	}
+	if wave >= HealerFirstWave && n%HealerEvery == HealerEvery-1 {
+		return EnemyHealer
+	}
+	return EnemyNormal
+}
+
+// takeDamage reduces a hit by the enemy's armor, applies it to the shield
+// first and any excess to HP, and restarts the shield's regeneration delay.
+// Returns the overkill: how far below zero this hit pushed HP (0 unless this
+// hit was the killing blow).
+func (e *Enemy) takeDamage(amount float64) float64 {
+	wasAlive := e.HP > 0
+	e.sinceHit = 0
+	if e.Armor > 0 {
+		amount = math.Max(amount-e.Armor, MinHitDamage)
+	}
+	absorbed := math.Min(e.Shield, amount)
+	e.Shield -= absorbed
+	e.HP -= amount - absorbed
+	if wasAlive && e.HP < 0 {
+		return -e.HP
+	}
+	return 0
+}
+
+// damageEnemy hits an enemy, applying any game rules that depend on the result.
+// An enemy guarded by its formation takes only part of the hit, and one in
+// a kill zone takes more from towers. The tower
+// the hit came from (nil if none) is credited with the damage it actually
+// did, and with the kill if it's the lethal blow.
+func (g *Game) damageEnemy(e *Enemy, amount float64, by *Tower) {
+	amount *= 1 - e.guard
+	if by != nil {
+		amount *= g.killZoneFactor(e.X, e.Y)
+	}
+	alive := e.HP > 0
+	before := max(e.HP, 0) + e.Shield
+	if overkill := e.takeDamage(amount); overkill > 0 {
+		g.rewardOverkill(e, overkill)
+	}
+	if by == nil {
+		return
+	}
+	g.recordTowerDamage(by, before-max(e.HP, 0)-e.Shield)
+	if alive && e.HP <= 0 {
+		by.creditKill()
+	}
+}
+
+// enemySpeed returns how far an enemy moves this tick: terrain and tar under
+// it change its speed (unless it flies over them), as does its kind, and rage and a final
+// sprint add to it. Tar and a chill don't stack; only the stronger slows it.
+func (g *Game) enemySpeed(e *Enemy) float64 {
+	if e.Flies() {
+		return EnemySpeed * orOne(EnemyTypes[e.Kind].Speed) * (1 - e.SlowFactor) * (1 + g.Rage()) * e.sprintFactor()
+	}
+	slow := max(g.slowAt(e.X, e.Y), e.SlowFactor)
+	return EnemySpeed * orOne(EnemyTypes[e.Kind].Speed) * g.terrainSpeedAt(e.X, e.Y) * (1 - slow) * (1 + g.Rage()) * e.sprintFactor()
+}
+
+// VelocityAt returns an enemy's current velocity in pixels per tick, heading
+// for its next waypoint at its actual speed. Zero once it has no waypoint
+// left, while stunned, or while a siege enemy is attacking a tower.
+func (g *Game) VelocityAt(e *Enemy) (vx, vy float64) {
+	if e.PathIndex >= len(e.Path) || e.Stunned > 0 || g.BlockingTower(e) != nil {
+		return 0, 0
+	}
+	target := e.Path[e.PathIndex]
+	tx, ty := CellCenter(target.X, target.Y)
+	dx, dy := Unit(tx-e.X, ty-e.Y)
+	speed := g.enemySpeed(e)
+	return dx * speed, dy * speed
+}
+
+// doomed returns true if the damage already committed to an enemy is enough
+// to finish it off (ignoring armor, so it errs toward still shooting)
+func (e *Enemy) doomed() bool {
+	return e.incoming > 0 && e.HP+e.Shield-e.incoming <= 0
+}
+
+// shredArmor strips armor (down to zero) and keeps it down for ShredDuration
+func (e *Enemy) shredArmor(amount float64) {
+	e.Armor = math.Max(e.Armor-amount, 0)
+	e.shredTimer = ShredDuration
+}
+
+// recoverArmor restores shredded armor once the shred wears off
+func (e *Enemy) recoverArmor() {
+	if e.shredTimer == 0 {
+		return
+	}
+	e.shredTimer--
+	if e.shredTimer == 0 {
+		e.Armor = EnemyTypes[e.Kind].Armor
+	}
+}
+
+// regenShield restores shield once the enemy has gone unhit for a while
+func (e *Enemy) regenShield() {
+	if e.ShieldMax == 0 {
+		return
+	}
+	if e.sinceHit < ShieldRegenDelay {
+		e.sinceHit++
+		return
+	}
+	e.Shield = math.Min(e.ShieldMax, e.Shield+ShieldRegenRate)
+}
+
+// EnemyCounts returns, per enemy kind, how many are alive and how many are
+// still to spawn this wave
+func (g *Game) EnemyCounts() (alive, queued []int) {
+	alive = make([]int, len(EnemyTypes))
+	queued = make([]int, len(EnemyTypes))
+	for _, e := range g.Enemies {
+		if e.HP > 0 {
+			alive[e.Kind]++
+		}
+	}
+	for n := g.WaveSpawned; n < g.WaveSpawned+g.EnemiesThisWave; n++ {
+		queued[g.WaveEnemyKind(g.CurrentWave, n)]++
+	}
+	return alive, queued
+}
+
+// TicksToNextSpawn returns how long until the next enemy appears, or -1 if
+// the current wave has nothing left to spawn
+func (g *Game) TicksToNextSpawn() int {
+	if g.EnemiesThisWave == 0 {
+		return -1
+	}
+	return g.WaveDelay + max(g.spawnTimer, 0)
+}
+
+// DangerRatio returns how close an enemy is to a base: 0 at the spawn end of
+// the longest lane, 1 at the base. Measured by steps left rather than PathIndex,
+// since rerouting restarts an enemy's PathIndex partway along.
+func (g *Game) DangerRatio(e *Enemy) float64 {
+	remaining := e.stepsLeft()
+	_, longest := g.LaneLengths()
+	longest = max(longest, remaining, 1)
+	return 1 - float64(remaining)/float64(longest)
+}
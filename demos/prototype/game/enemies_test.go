@@ -0,0 +1,71 @@
+package game
+
+import "testing"
+
+func TestShreddedArmorRaisesDamageTakenUntilItWearsOff(t *testing.T) {
+	g := newTestGame(t, openField...)
+	basic := buildTower(t, g, 5, 1, KindBasic)
+	shredder := buildTower(t, g, 6, 1, KindShredder)
+	g.spawnEnemy(EnemyArmored)
+	e := g.Enemies[0]
+	e.HP = 1e6 // Survives every hit
+	hit := func() float64 {
+		hp := e.HP
+		g.hitEnemy(basic, e, TowerDamage)
+		return hp - e.HP
+	}
+
+	armored := hit()
+	g.hitEnemy(shredder, e, 0)
+	shredded := hit()
+	if shredded <= armored {
+		t.Errorf("hit after shred = %v, want more than %v", shredded, armored)
+	}
+
+	for range ShredDuration {
+		e.recoverArmor()
+	}
+	if recovered := hit(); recovered != armored {
+		t.Errorf("hit after the shred wore off = %v, want %v", recovered, armored)
+	}
+}
+
+// standardKinds returns every enemy kind a game without endless mode sends
+// over its TotalWaves
+func standardKinds(g *Game) map[EnemyKind]bool {
+	kinds := make(map[EnemyKind]bool)
+	for wave := 1; wave <= TotalWaves; wave++ {
+		for n := range g.WaveSize(wave) {
+			kinds[g.WaveEnemyKind(wave, n)] = true
+		}
+	}
+	return kinds
+}
+
+func TestStandardGameSendsFlyersAndWalkers(t *testing.T) {
+	g := newTestGame(t, openField...)
+	var flyers, walkers int
+	for kind := range standardKinds(g) {
+		if EnemyTypes[kind].Flies {
+			flyers++
+		} else {
+			walkers++
+		}
+	}
+	if flyers == 0 || walkers == 0 {
+		t.Errorf("%d TotalWaves send %d flying and %d ground kinds, want some of each", TotalWaves, flyers, walkers)
+	}
+}
+
+func TestStandardGameSendsEveryKind(t *testing.T) {
+	g := newTestGame(t, openField...)
+	kinds := standardKinds(g)
+	for kind, et := range EnemyTypes {
+		if !et.Boss && !kinds[EnemyKind(kind)] {
+			t.Errorf("no %s in %d TotalWaves", et.Name, TotalWaves)
+		}
+	}
+	if !kinds[EnemyBoss] {
+		t.Errorf("no boss in %d TotalWaves", TotalWaves)
+	}
+}
@@ -0,0 +1,48 @@
+package game
+
+import "container/heap"
+
+// FlowField maps every walkable cell that can reach a base to the neighbor
+// it should step onto next, by the same step costs A* uses. It's the route
+// an enemy standing on that cell would take.
+func (g *Game) FlowField() map[Point]Point {
+	dist := make(map[Point]int)
+	openSet := &priorityQueue{}
+	heap.Init(openSet)
+	for i, b := range g.Bases {
+		dist[b] = 0
+		heap.Push(openSet, &pqItem{point: b, priority: 0, order: i})
+	}
+	pushed := len(g.Bases)
+	dirs := []Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+	for openSet.Len() > 0 {
+		item := heap.Pop(openSet).(*pqItem)
+		if item.priority > dist[item.point] {
+			continue // Stale; reached more cheaply since
+		}
+		for _, d := range dirs {
+			n := Point{X: item.point.X + d.X, Y: item.point.Y + d.Y}
+			if !g.isWalkable(n.X, n.Y) {
+				continue
+			}
+			cost := item.priority + g.stepCost(item.point)
+			if old, ok := dist[n]; !ok || cost < old {
+				dist[n] = cost
+				heap.Push(openSet, &pqItem{point: n, priority: cost, order: pushed})
+				pushed++
+			}
+		}
+	}
+
+	flow := make(map[Point]Point, len(dist))
+	for p, cost := range dist {
+		for _, d := range dirs {
+			n := Point{X: p.X + d.X, Y: p.Y + d.Y}
+			if nd, ok := dist[n]; ok && nd+g.stepCost(n) == cost {
+				flow[p] = n
+				break
+			}
+		}
+	}
+	return flow
+}
@@ -0,0 +1,17 @@
+package game
+
+// Flyers take no notice of the maze. Their route is a single straight leg
+// from their spawn to a base (the nearest the routing policy allows), so the
+// waypoint-following in updateEnemies carries them directly to the base
+// center, and grid changes leave them alone.
+
+// Flies returns true if an enemy flies over the grid rather than walking it
+func (e *Enemy) Flies() bool {
+	return EnemyTypes[e.Kind].Flies
+}
+
+// flightPath returns the route of a flyer from a spawn: straight to the
+// nearest of bases, reusing buf
+func flightPath(buf []Point, spawn Point, bases []Point) []Point {
+	return append(buf[:0], spawn, nearestBase(bases, spawn))
+}
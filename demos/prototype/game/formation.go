@@ -0,0 +1,33 @@
+package game
+
+import "math"
+
+const (
+	FormationRadius   = 40.0 // Pixels within which same-kind enemies count as grouped
+	FormationBonus    = 0.15 // Damage reduction per grouped neighbor
+	FormationMaxGuard = 0.6  // Cap on the damage reduction
+)
+
+// FormationLinked returns true if two living enemies are close enough, and
+// alike enough, to guard each other
+func FormationLinked(a, b *Enemy) bool {
+	return a.Kind == b.Kind && a.HP > 0 && b.HP > 0 && math.Hypot(a.X-b.X, a.Y-b.Y) <= FormationRadius
+}
+
+// updateFormations sets each enemy's guard from how many same-kind enemies
+// are near it. With formations off nobody is guarded.
+func (g *Game) updateFormations() {
+	for _, e := range g.Enemies {
+		e.guard = 0
+		if !g.FormationsEnabled {
+			continue
+		}
+		n := 0
+		for _, o := range g.Enemies {
+			if o != e && FormationLinked(e, o) {
+				n++
+			}
+		}
+		e.guard = math.Min(float64(n)*FormationBonus, FormationMaxGuard)
+	}
+}
@@ -0,0 +1,1083 @@
+// Package game is the tower defense simulation: grid, pathfinding, enemies,
+// towers, waves, and economy. It advances only through Step and knows
+// nothing about drawing or input devices.
+package game
+
+import (
+	"container/heap"
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// GameState represents the current state of the game
+type GameState int
+
+const (
+	StatePlaying GameState = iota
+	StateWon
+	StateLost
+)
+
+// Point represents a grid coordinate
+type Point struct {
+	X, Y int
+}
+
+// Priority queue for A*
+type pqItem struct {
+	point    Point
+	priority int // f = g + h
+	order    int // Push sequence number; breaks priority ties first-in, first-out
+	index    int
+}
+
+type priorityQueue []*pqItem
+
+func (pq priorityQueue) Len() int { return len(pq) }
+
+func (pq priorityQueue) Less(i, j int) bool {
+	if pq[i].priority != pq[j].priority {
+		return pq[i].priority < pq[j].priority
+	}
+	return pq[i].order < pq[j].order
+}
+
+func (pq priorityQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+func (pq *priorityQueue) Push(x any) {
+	n := len(*pq)
+	item := x.(*pqItem)
+	item.index = n
+	*pq = append(*pq, item)
+}
+
+func (pq *priorityQueue) Pop() any {
+	old := *pq
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*pq = old[0 : n-1]
+	return item
+}
+
+const (
+	// Default grid dimensions
+	GridWidth   = 20
+	GridHeight  = 15
+	MinGridSize = 5 // Fewest cells along either side: a border wall around room for a spawn and base
+
+	// Cell size in pixels
+	CellSize = 40
+
+	// Logic ticks per second
+	TPS = 60
+)
+
+// TileType represents what's in a cell
+type TileType int
+
+const (
+	TileEmpty TileType = iota
+	TileGround
+	TileWall
+	TileBase
+	TileSpawn
+	TileTower
+	TileMud  // Walkable; slows enemies
+	TileRoad // Walkable; speeds enemies up
+	TileVoid // Not part of the board, for irregular maps; drawn as background
+)
+
+var enemyColor = color.RGBA{R: 255, G: 100, B: 100, A: 255}
+
+const (
+	EnemySpeed    = 2.0   // Pixels per tick
+	CornerRadius  = 10.0  // Pixels before a turn where enemies start rounding it; 0 = sharp turns
+	SpawnInterval = 40    // Ticks between spawns within a wave
+	EnemyRadius   = 12.0  // Visual radius
+	EnemyMaxHP    = 100.0 // Starting HP
+
+	TowerRange    = 120.0 // Pixels
+	TowerDamage   = 10.0  // Damage per shot
+	TowerCooldown = 30    // Ticks between shots
+	LaserDuration = 5     // Ticks to show laser
+
+	// Game balance
+	TotalWaves       = 5   // Waves to survive to win
+	EnemiesPerWave   = 5   // Base enemies per wave (scales with wave number)
+	WaveDelay        = 180 // Ticks between waves
+	AutoWaveDelay    = 30  // Ticks between waves when they auto-start back to back
+	StartingResource = 100 // Resources at game start
+	TowerCost        = 25  // Cost to place a tower
+	KillReward       = 10  // Resources earned per kill
+	TowerLimit       = 15  // Max towers on the board in tower limit mode
+
+	RecycleHeatPenalty = 0.3 // Refund lost selling a just-fired tower, in realistic economy
+
+	MessageDuration = 120 // Ticks to show a status message
+
+	MazeBonusPerCell   = 2    // Resources per cell the path grows beyond its longest
+	OverkillRewardRate = 0.05 // Resources per point of overkill damage, when enabled
+	FloatTextTTL       = 60   // Ticks a floating text stays up
+	FloatTextRise      = 0.5
+)
+
+// Enemy represents a moving enemy
+type Enemy struct {
+	Kind         EnemyKind
+	X, Y         float64 // Position in pixels
+	PathIndex    int     // Current target waypoint in path
+	Path         []Point // Enemy's own copy of the path
+	HP           float64 // Current health
+	Shield       float64 // Absorbs damage before HP
+	ShieldMax    float64 // Shield regenerates up to this
+	Reward       int     // Resources granted when killed
+	Armor        float64 // Current armor; shredding lowers it below the kind's base
+	sinceHit     int     // Ticks since last damaged, for shield regen
+	shredTimer   int     // Ticks until shredded armor recovers
+	guard        float64 // Fraction of damage blocked by nearby same-kind enemies
+	bounceImmune int     // Ticks until bounce pads can shove this enemy again
+	Burrowed     int     // Ticks left underground; untargetable while above 0
+	surfaceTimer int     // Ticks until a burrower digs in again
+	Stunned      int     // Ticks left unable to move
+	stunImmune   int     // Ticks until it can be stunned again
+	SlowFactor   float64 // Fraction of speed lost to a chill
+	SlowTTL      int     // Ticks left chilled
+	incoming     float64 // Damage committed to it (fired, not yet landed)
+}
+
+// Tower represents a placed tower
+type Tower struct {
+	X, Y       int       // Grid position
+	Kind       TowerKind // What type of tower this is
+	Cooldown   int       // Ticks until can fire again
+	Facing     float64   // Radians; only directional towers use it
+	BuildTimer int       // Ticks until construction finishes; can't fire before then
+	HP         float64   // Health; enemies only attack towers in siege mode
+	Target     *Enemy    // Current target, kept while it stays valid
+
+	ShotsRemaining int // Shots left before a limited tower is used up
+	Kills          int // Enemies it dealt the lethal blow to
+	KillStacks     int // Permanent damage stacks from kills, for kinds that level up
+	Level          int // Upgrade level, 1 to MaxTowerLevel
+	Invested       int // Resources spent building and upgrading it
+
+	BeamCharge float64 // Ramp bonus built up on the current target; lost on switching
+
+	TargetMode TargetMode // Which valid enemy it picks when it needs a new target
+
+	ManualFire bool // Only fires when the player clicks it
+	FireQueued bool // Player clicked; fire at the next chance
+}
+
+// Laser represents a visual shot effect
+type Laser struct {
+	FromX, FromY float64
+	ToX, ToY     float64
+	TTL          int        // Ticks remaining to display
+	Trail        int        // Ticks the faint trail left after TTL runs out lasts
+	Color        color.RGBA // Zero for the palette's laser color
+	Width        float32
+}
+
+// FloatingText is a short message that drifts up from a point and fades
+type FloatingText struct {
+	X, Y float64 // Position in pixels
+	Text string
+	TTL  int // Ticks remaining to display
+}
+
+// Game holds the game state
+type Game struct {
+	Grid [][]TileType // Rows of cells, sized when the game starts
+
+	// Pathfinding
+	Spawns      []Point       // Where enemies enter, one lane each
+	Bases       []Point       // What's defended; an enemy reaching any of them wins
+	Paths       [][]Point     // Current path of each lane, from its spawn to the nearest base (nil if blocked)
+	Routing     RoutingPolicy // Which base each enemy heads for
+	BaseHP      []float64     // Each base's health, by index into Bases; nil until one takes damage
+	nextBase    int           // Base round-robin routing sends the next enemy to
+	PathBlocked bool          // True if any lane has no valid path
+	NextLane    int           // Lane the next enemy enters, taken in turn
+	SpawnStall  int           // Ticks spawning has waited on a blocked path
+	LongestPath int           // Longest path length (in steps) achieved so far
+
+	// Debug overlay
+	ShowDebug bool    // Show pathfinding internals
+	Explored  []Point // Cells A* expanded for the current path (debug only)
+
+	// Enemies
+	Enemies     []*Enemy
+	freeEnemies []*Enemy // Removed enemies, for reuse
+	spawnTimer  int      // Ticks until next spawn
+
+	// Towers
+	Towers             []*Tower
+	Traps              []*Trap         // Armed miner traps
+	Slow               [][]float64     // Speed lost per cell to tar pits
+	Lasers             []*Laser        // Visual effects for shots
+	FloatTexts         []*FloatingText // Rising reward/info texts
+	freeLasers         []*Laser        // Faded lasers, for reuse
+	Explosions         []*Explosion    // Visual effects for splash hits
+	Projectiles        []*Projectile   // Shells in flight
+	BuildQueueEnabled  bool            // Clicks queue placements instead of buying outright
+	BuildQueue         []QueuedBuild   // Placements built in order as resources allow
+	LastLayout         []QueuedBuild   // Towers standing when the last game ended, offered again on restart
+	RequireLineOfSight bool            // Towers can't shoot through walls
+	SmoothPaths        bool            // Enemies cut straight across open ground
+	SkipDoomed         bool            // Towers don't target enemies that incoming damage will kill
+	Separation         bool            // Enemies sharing a cell push apart sideways
+
+	// Coverage gaps
+	GapCells      []Point // Cached uncovered path cells
+	CoverageDirty bool    // GapCells needs recomputing
+
+	// Flow field
+	Flow map[Point]Point // Cached next step from each cell toward the base; nil when stale
+
+	// Enemy approaching base warning
+	BaseAlert    float64 // 0 = no enemy close; rises to 1 as one reaches the base
+	alarmTimer   int     // Ticks until the next alarm beep
+	AlarmEnabled bool    // Beep while the base is under threat
+
+	// Enemy formations
+	FormationsEnabled bool // Same-kind enemies guard each other while grouped
+
+	// Kill zone ability
+	KillZone     *KillZone // Active zone; nil when none
+	ZoneCooldown int       // Ticks until another kill zone can be called
+
+	// Resource orbs dropped by dying enemies
+	Pickups        []Pickup
+	PickupsEnabled bool // Enemies may drop orbs to click for resources
+
+	// The last enemies of a wave rage
+	RageEnabled bool // A wave's last survivors speed up as it dwindles
+
+	// Game state
+	State       GameState
+	Surrendered bool // The player gave up rather than the base falling
+	Resources   int
+	Tick        int // Simulation ticks since the game started; the one game clock
+
+	// Wave system
+	CurrentWave     int  // Current wave number (1-indexed)
+	clearedWave     int  // Last wave whose clear bonus was paid
+	waveNearLeak    bool // An enemy set off the base alert this wave
+	EnemiesThisWave int  // Enemies remaining to spawn this wave
+	WaveSpawned     int  // Enemies spawned so far this wave
+	WaveDelay       int  // Ticks until next wave starts
+	TotalKills      int  // Total enemies killed
+	totalSpawned    int  // Total enemies spawned, numbering their HP rolls
+
+	// Map source
+	Generated bool   // Map came from GenerateMap
+	MapSeed   int64  // Seed the map was generated from
+	Tutorial  bool   // Map is the tutorial layout with starter towers
+	MapName   string // Saved map the game is on; "" for built-in and generated maps
+	TwoLanes  bool   // Map is the two-lane layout
+
+	// Practice mode
+	PracticeWave int // Wave an unscored practice run started at; 0 in normal play
+
+	// Daily challenge
+	Daily     bool       // Playing a date-seeded challenge
+	DailyDate time.Time  // Day the challenge is for
+	DailyBest int        // Best score for that day before this run
+	Seed      uint64     // Seeds every random decision (see rngFor)
+	pcg       rand.PCG   // Reseeded by rngFor for each decision
+	rng       *rand.Rand // Draws from pcg
+	finished  bool       // End-of-game bookkeeping done
+
+	// Endless mode
+	Endless   bool // Keep going past TotalWaves
+	MegaWave  bool // Current wave is a milestone mega wave
+	MegaFlash int  // Ticks left of the mega wave background flash
+
+	// Per-wave performance, used by the opt-in surge
+	SurgeEnabled  bool    // Inject extra enemies when a wave is too easy
+	surgeKills    int     // Enemies killed this wave since the last surge
+	WaveKills     int     // Enemies killed this wave
+	surgeProgress float64 // Sum of path progress (0..1) of those enemies
+	WaveSurges    int     // Surges triggered this wave
+
+	// Damage per tower kind, for the between-waves report
+	waveDamage     map[TowerKind]float64 // Dealt so far this wave
+	LastWaveDamage map[TowerKind]float64 // Dealt in the wave just cleared
+
+	// Opt-in overkill rule
+	OverkillEnabled bool    // Wasted damage on killing blows pays out resources
+	overkillCarry   float64 // Fractional resources not yet paid out
+	TotalOverkill   float64 // All overkill damage this game, for the end screen
+
+	// Opt-in siege mode
+	SiegeEnabled bool // Enemies attack towers in their way instead of rerouting
+
+	// Realistic economy
+	RealisticEconomy bool // Sell refunds depend on the tower's heat
+
+	// Tower limit mode
+	MaxTowers int // Most towers allowed on the board at once; 0 = unlimited
+
+	// Front end
+	Effects        Effects // Sounds and slow motion the simulation cues
+	TracerTicks    int     // Ticks a shot's tracer stays bright
+	ShotTrails     bool    // Shots leave a fading trail behind their tracer
+	AutoStartWaves bool    // Waves start sooner after a clear
+
+	// Transient status message
+	Message    string
+	MessageTTL int
+}
+
+// NewGame creates a new game on the built-in layout, stretched to a grid of
+// the given size (at least MinGridSize each way)
+func NewGame(width, height int) *Game {
+	return newGameFromMap(defaultMap(max(width, MinGridSize), max(height, MinGridSize)))
+}
+
+// newGameFromMap creates a new game on a map
+func newGameFromMap(m *Map) *Game {
+	g := &Game{Grid: m.Tiles, Spawns: m.Spawns, Bases: m.Bases}
+	g.placeStartingTowers(m.Towers)
+
+	// Calculate initial path (after starting towers, which reshape it)
+	g.RecalculatePath()
+	g.LongestPath = g.PathLength()
+	g.recalculateSlow()
+	g.CoverageDirty = true
+
+	// Initialize game state
+	g.State = StatePlaying
+	g.Resources = StartingResource
+	g.CurrentWave = 1
+	g.EnemiesThisWave = g.WaveSize(1)
+	g.WaveDelay = 300 // 5 seconds to place initial towers
+	g.TracerTicks = LaserDuration
+	g.Effects = noEffects{}
+
+	return g
+}
+
+// Surrender ends a game in progress as a loss. Enemies still on the board
+// are cleared away and nothing more spawns, so the end screen shows the run
+// as it stood.
+func (g *Game) Surrender() {
+	if g.State != StatePlaying {
+		return
+	}
+	for _, e := range g.Enemies {
+		g.releaseEnemy(e)
+	}
+	clear(g.Enemies)
+	g.Enemies = g.Enemies[:0]
+	g.EnemiesThisWave = 0
+	g.Surrendered = true
+	g.State = StateLost
+	g.finishIfOver()
+}
+
+// ShowMessage displays a short status message below the status bar
+func (g *Game) ShowMessage(format string, args ...any) {
+	g.Message = fmt.Sprintf(format, args...)
+	g.MessageTTL = MessageDuration
+}
+
+// isWalkable returns true if a tile can be walked through
+func (g *Game) isWalkable(x, y int) bool {
+	if !g.InGrid(x, y) {
+		return false
+	}
+	tile := g.Grid[y][x]
+	return tile == TileGround || tile == TileSpawn || tile == TileBase || tile == TileMud || tile == TileRoad
+}
+
+// heuristic calculates Manhattan distance
+func heuristic(a, b Point) int {
+	dx := a.X - b.X
+	dy := a.Y - b.Y
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	return dx + dy
+}
+
+// findPath uses A* to find path from start to goal
+func (g *Game) findPath(start, goal Point) []Point {
+	return g.searchPath(start, goal, nil)
+}
+
+// searchPath is A* from start to goal. If explored is non-nil, every cell the
+// search expands is appended to it (once, in expansion order).
+func (g *Game) searchPath(start, goal Point, explored *[]Point) []Point {
+	var expanded map[Point]bool
+	if explored != nil {
+		expanded = make(map[Point]bool)
+	}
+
+	openSet := &priorityQueue{}
+	heap.Init(openSet)
+	heap.Push(openSet, &pqItem{point: start, priority: 0})
+	pushed := 1
+
+	cameFrom := make(map[Point]Point)
+	gScore := make(map[Point]int)
+	gScore[start] = 0
+
+	// 4-directional movement
+	dirs := []Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+
+	for openSet.Len() > 0 {
+		current := heap.Pop(openSet).(*pqItem).point
+		if explored != nil && !expanded[current] {
+			expanded[current] = true
+			*explored = append(*explored, current)
+		}
+
+		if current == goal {
+			// Reconstruct path
+			path := []Point{current}
+			for current != start {
+				current = cameFrom[current]
+				path = append([]Point{current}, path...)
+			}
+			return path
+		}
+
+		for _, d := range dirs {
+			neighbor := Point{X: current.X + d.X, Y: current.Y + d.Y}
+
+			if !g.isWalkable(neighbor.X, neighbor.Y) {
+				continue
+			}
+
+			tentativeG := gScore[current] + g.stepCost(neighbor)
+
+			if oldG, exists := gScore[neighbor]; !exists || tentativeG < oldG {
+				cameFrom[neighbor] = current
+				gScore[neighbor] = tentativeG
+				f := tentativeG + heuristic(neighbor, goal)*MinStepCost // Stays admissible on roads
+				heap.Push(openSet, &pqItem{point: neighbor, priority: f, order: pushed})
+				pushed++
+			}
+		}
+	}
+
+	// No path found
+	return nil
+}
+
+// RecalculatePath updates each lane's path from its spawn to the nearest base
+func (g *Game) RecalculatePath() {
+	g.Explored = nil
+	var explored *[]Point
+	if g.ShowDebug {
+		explored = &g.Explored
+	}
+	g.Paths = make([][]Point, len(g.Spawns))
+	g.PathBlocked = false
+	for i, s := range g.Spawns {
+		g.Paths[i] = g.pathToBase(s, explored)
+		if g.Paths[i] == nil {
+			g.PathBlocked = true
+		}
+	}
+}
+
+// OnGridChanged recalculates everything derived from the grid. blocked lists
+// cells that became obstacles; opened is true if any cell became walkable.
+func (g *Game) OnGridChanged(blocked []Point, opened bool) {
+	g.RecalculatePath()
+	g.recalculateSlow()
+	if opened {
+		g.RecalculateEnemyPaths(nil)
+	} else {
+		g.RecalculateEnemyPaths(blocked)
+	}
+	g.CoverageDirty = true
+	g.Flow = nil
+}
+
+// PathLength returns the number of steps from spawn to base, summed over
+// every lane (blocked lanes count 0)
+func (g *Game) PathLength() int {
+	n := 0
+	for _, path := range g.Paths {
+		if len(path) > 0 {
+			n += len(path) - 1
+		}
+	}
+	return n
+}
+
+// rewardMazing grants a one-time bonus when the path grows beyond its longest
+// length so far. Shortening the path never costs anything.
+func (g *Game) rewardMazing(x, y int) {
+	length := g.PathLength()
+	if length <= g.LongestPath {
+		return
+	}
+	bonus := (length - g.LongestPath) * MazeBonusPerCell
+	g.LongestPath = length
+	g.Resources += bonus
+	px, py := CellCenter(x, y)
+	g.AddFloatingText(px, py, fmt.Sprintf("+%d maze", bonus))
+}
+
+// rewardOverkill turns part of a killing blow's wasted damage into resources
+// when the overkill rule is on. Fractions carry over to the next kill.
+func (g *Game) rewardOverkill(e *Enemy, overkill float64) {
+	g.TotalOverkill += overkill
+	if !g.OverkillEnabled {
+		return
+	}
+	g.overkillCarry += overkill * OverkillRewardRate
+	bonus := int(g.overkillCarry)
+	if bonus == 0 {
+		return
+	}
+	g.overkillCarry -= float64(bonus)
+	g.Resources += bonus
+	g.AddFloatingText(e.X, e.Y, fmt.Sprintf("+%d overkill", bonus))
+}
+
+// RecalculateEnemyPaths updates enemy paths from their current position.
+// Only enemies whose remaining path crosses one of the changed cells are rerouted;
+// pass nil to reroute everyone (e.g. when a cell opens up and any route may get shorter).
+func (g *Game) RecalculateEnemyPaths(changed []Point) {
+	for _, e := range g.Enemies {
+		// Flyers' routes don't depend on the grid
+		if e.Flies() {
+			continue
+		}
+		// Smoothed routes cross cells that aren't waypoints, so any may be hit
+		if changed != nil && !g.smoothing() && !pathCrosses(e.Path[e.PathIndex:], changed) {
+			continue
+		}
+		// In siege mode enemies hold their route and attack whatever blocks it
+		if changed != nil && g.SiegeEnabled {
+			continue
+		}
+
+		// Get enemy's current grid cell
+		gridX := int(e.X) / CellSize
+		gridY := int(e.Y) / CellSize
+		currentCell := Point{X: gridX, Y: gridY}
+
+		// Find new path from current position to a base the routing policy allows
+		newPath := g.routeTo(currentCell, g.rerouteChoices(e))
+		if newPath != nil {
+			e.Path = g.smoothPath(newPath)
+			e.PathIndex = 1 // Start moving toward second waypoint
+		}
+		// If no path, enemy keeps current path (will walk through obstacle)
+		// This matches the "enemies can break through" design
+	}
+}
+
+// pathCrosses reports whether any point of path is one of cells
+func pathCrosses(path []Point, cells []Point) bool {
+	for _, p := range path {
+		for _, c := range cells {
+			if p == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// spawnEnemy creates a new enemy of the given kind at the next lane's spawn
+// point. Flyers take off even while the ground route is blocked.
+func (g *Game) spawnEnemy(kind EnemyKind) {
+	lane := g.NextLane % len(g.Spawns)
+	flies := EnemyTypes[kind].Flies
+	if !flies && (g.PathBlocked || len(g.Paths[lane]) == 0) {
+		return
+	}
+	g.NextLane = (lane + 1) % len(g.Spawns)
+	spawn := g.Spawns[lane]
+	bases := g.spawnChoices()
+	route := g.Paths[lane]
+	if !flies && len(bases) < len(g.Bases) {
+		route = g.routeTo(spawn, bases)
+	}
+	e := g.newEnemy()
+	*e = Enemy{
+		Kind:      kind,
+		X:         float64(spawn.X*CellSize) + CellSize/2,
+		Y:         float64(spawn.Y*CellSize) + CellSize/2,
+		PathIndex: 1,                            // Start moving toward second waypoint (first is spawn)
+		Path:      append(e.Path[:0], route...), // Own copy of its lane's path, or of its own route
+		HP:        g.spawnHP(kind),
+		Shield:    EnemyTypes[kind].ShieldMax,
+		ShieldMax: EnemyTypes[kind].ShieldMax,
+		Armor:     EnemyTypes[kind].Armor,
+		Reward:    g.killReward(kind),
+		// Burrowers walk a while before first digging in
+		surfaceTimer: SurfaceDuration,
+	}
+	if flies {
+		e.Path = flightPath(e.Path, spawn, bases)
+	} else {
+		e.Path = g.smoothPath(e.Path)
+	}
+	g.Enemies = append(g.Enemies, e)
+	g.totalSpawned++
+}
+
+// addTower places a tower of the given kind and tracks it (returns false if
+// it can't be afforded or the tower limit is reached)
+func (g *Game) addTower(x, y int, kind TowerKind) bool {
+	if g.MaxTowers > 0 && len(g.Towers) >= g.MaxTowers {
+		g.ShowMessage("Tower limit reached (%d)", g.MaxTowers)
+		return false
+	}
+	cost := TowerTypes[kind].Cost
+	if g.Resources < cost {
+		return false
+	}
+	g.Resources -= cost
+	g.Grid[y][x] = TileTower
+	t := g.newTower(x, y, kind)
+	t.BuildTimer = TowerBuildTime
+	g.Towers = append(g.Towers, t)
+	return true
+}
+
+// newTower creates a ready-to-fire tower (it doesn't touch the grid)
+func (g *Game) newTower(x, y int, kind TowerKind) *Tower {
+	return &Tower{
+		X:              x,
+		Y:              y,
+		Kind:           kind,
+		Facing:         g.facePath(x, y),
+		HP:             TowerMaxHP,
+		ShotsRemaining: TowerTypes[kind].Shots,
+		Level:          1,
+		Invested:       TowerTypes[kind].Cost,
+	}
+}
+
+// TowerAt returns the tower on a cell, or nil
+func (g *Game) TowerAt(x, y int) *Tower {
+	for _, t := range g.Towers {
+		if t.X == x && t.Y == y {
+			return t
+		}
+	}
+	return nil
+}
+
+// RemoveTower removes a tower and refunds part of its cost
+func (g *Game) RemoveTower(x, y int) {
+	if t := g.TowerAt(x, y); t != nil {
+		g.Resources += g.SellValue(t)
+		g.deleteTower(t)
+	}
+}
+
+// SellValue returns what selling a tower refunds: half of what was spent on
+// it, or with the realistic economy less the hotter it is (the more of its
+// cooldown remains), so firing and immediately selling doesn't pay
+func (g *Game) SellValue(t *Tower) int {
+	refund := t.Invested / 2
+	if !g.RealisticEconomy {
+		return refund
+	}
+	heat := float64(t.Cooldown) / float64(max(g.EffectiveStats(t).Cooldown, 1))
+	return int(float64(refund) * (1 - RecycleHeatPenalty*heat))
+}
+
+// deleteTower takes a tower off the board, with no refund
+func (g *Game) deleteTower(t *Tower) {
+	g.Grid[t.Y][t.X] = TileGround
+	// Remove from tower list
+	for i, o := range g.Towers {
+		if o == t {
+			g.Towers = append(g.Towers[:i], g.Towers[i+1:]...)
+			break
+		}
+	}
+}
+
+// updateEnemies moves all enemies along the path
+func (g *Game) updateEnemies() {
+	g.healEnemies()
+	alive := g.Enemies[:0] // Compacted in place
+	var leaked []int       // Index of the base each leak reached
+
+	for _, e := range g.Enemies {
+		// Remove dead enemies and grant reward
+		if e.HP <= 0 {
+			g.Resources += e.Reward
+			g.TotalKills++
+			g.recordWaveKill(e)
+			g.maybeDropPickup(e)
+			if EnemyTypes[e.Kind].Boss {
+				g.Effects.BossKilled()
+			}
+			g.releaseEnemy(e)
+			continue
+		}
+		g.updateEnemyAbilities(e)
+
+		if e.PathIndex >= len(e.Path) {
+			// Enemy reached its base
+			leaked = append(leaked, g.leakedBase(e))
+			g.releaseEnemy(e)
+			continue
+		}
+
+		// Stunned enemies stand still (and don't attack)
+		if e.updateStun() {
+			alive = append(alive, e)
+			continue
+		}
+
+		// In siege mode a tower in the way is attacked, not walked around
+		if t := g.BlockingTower(e); t != nil {
+			g.attackTower(t)
+			alive = append(alive, e)
+			continue
+		}
+
+		// Get target waypoint center (from enemy's own path)
+		target := e.Path[e.PathIndex]
+		targetX := float64(target.X*CellSize) + CellSize/2
+		targetY := float64(target.Y*CellSize) + CellSize/2
+
+		speed := g.enemySpeed(e)
+
+		// Calculate direction
+		dx := targetX - e.X
+		dy := targetY - e.Y
+		dist := math.Sqrt(dx*dx + dy*dy)
+
+		// Near a turn, blend toward the next segment so the corner is rounded
+		if dist < CornerRadius && dist >= speed && e.PathIndex > 0 && e.PathIndex+1 < len(e.Path) {
+			prev, next := e.Path[e.PathIndex-1], e.Path[e.PathIndex+1]
+			inX, inY := Unit(float64(target.X-prev.X), float64(target.Y-prev.Y))   // Direction in
+			outX, outY := Unit(float64(next.X-target.X), float64(next.Y-target.Y)) // Direction out
+			if (e.X-targetX)*inX+(e.Y-targetY)*inY >= 0 {
+				// Already level with the waypoint: it counts as reached
+				e.PathIndex++
+				alive = append(alive, e)
+				continue
+			}
+			w := 1 - dist/CornerRadius
+			dx = dx/dist*(1-w) + outX*w
+			dy = dy/dist*(1-w) + outY*w
+			dist = math.Hypot(dx, dy)
+			e.X += (dx / dist) * speed
+			e.Y += (dy / dist) * speed
+			alive = append(alive, e)
+			continue
+		}
+
+		if dist < speed {
+			// Reached waypoint, move to next
+			e.X = targetX
+			e.Y = targetY
+			e.PathIndex++
+		} else {
+			// Move toward waypoint
+			e.X += (dx / dist) * speed
+			e.Y += (dy / dist) * speed
+		}
+
+		alive = append(alive, e)
+	}
+
+	clear(g.Enemies[len(alive):]) // Drop stale pointers past the end
+	g.Enemies = alive
+	g.chargeBases(leaked)
+	for _, e := range g.Enemies {
+		g.bounceEnemy(e)
+	}
+	if g.Separation {
+		g.separateEnemies()
+	}
+	g.updateBaseAlert()
+}
+
+// updateTowers handles tower targeting and shooting
+func (g *Game) updateTowers() {
+	var spent []*Tower // Used up this tick; removed after the loop
+	for _, t := range g.Towers {
+		stats := g.EffectiveStats(t)
+
+		// Towers under construction do nothing yet
+		if t.BuildTimer > 0 {
+			t.BuildTimer--
+			if t.BuildTimer == 0 && TowerTypes[t.Kind].Slow > 0 {
+				g.recalculateSlow() // The finished tar pit starts slowing
+			}
+			continue
+		}
+
+		// Tar pits slow passively and never shoot
+		if TowerTypes[t.Kind].Slow > 0 {
+			continue
+		}
+
+		// Decrease cooldown
+		if t.Cooldown > 0 {
+			t.Cooldown--
+			continue
+		}
+
+		// Bounce pads act when enemies step by (see bounceEnemy)
+		if TowerTypes[t.Kind].Bounce {
+			continue
+		}
+
+		// Miners lay traps instead of shooting
+		if TowerTypes[t.Kind].Traps {
+			if g.seedTrap(t, stats) {
+				t.Cooldown = stats.Cooldown
+			}
+			continue
+		}
+
+		// Directional towers hit everything inside their cone
+		if TowerTypes[t.Kind].Arc > 0 {
+			g.fireCone(t, stats)
+			continue
+		}
+
+		towerX, towerY := CellCenter(t.X, t.Y)
+
+		// Stick with the current target while it stays valid, so near-equal
+		// candidates don't make the tower flip-flop between ticks
+		target := t.Target
+		if !g.CanTarget(t, stats, target) {
+			target = g.selectTarget(t, stats)
+		}
+		if target != t.Target {
+			t.BeamCharge = 0
+		}
+		t.Target = target
+
+		// Manual towers hold fire until the player triggers them, and hit harder
+		damage := stats.Damage * (1 + t.BeamCharge)
+		if t.ManualFire {
+			if !t.FireQueued {
+				continue
+			}
+			damage *= ManualFireBonus
+		}
+
+		// Fire at target: cannons launch a shell that hits on arrival
+		if target != nil {
+			shell := TowerTypes[t.Kind].ShellSpeed > 0
+			if shell {
+				g.fireProjectile(t, target, damage)
+			} else {
+				g.hitEnemy(t, target, damage)
+			}
+			t.chargeBeam()
+			t.Cooldown = stats.Cooldown
+			t.FireQueued = false
+			if TowerTypes[t.Kind].Shots > 0 {
+				t.ShotsRemaining--
+				if t.ShotsRemaining <= 0 {
+					spent = append(spent, t)
+				}
+			}
+			g.Effects.Shot()
+
+			// Create laser visual
+			if !shell {
+				chargedBeam(g.addLaser(t.Kind, towerX, towerY, target.X, target.Y), t)
+			}
+		}
+	}
+
+	// Freed cells may shorten the enemies' route
+	for _, t := range spent {
+		g.consumeTower(t)
+	}
+	if len(spent) > 0 {
+		g.OnGridChanged(nil, true)
+	}
+}
+
+// hitEnemy lands a tower's shot on an enemy: the tower's on-hit effects, the
+// damage, and any splash around it
+func (g *Game) hitEnemy(t *Tower, target *Enemy, damage float64) {
+	if shred := TowerTypes[t.Kind].Shred; shred > 0 {
+		target.shredArmor(shred)
+	}
+	if stun := TowerTypes[t.Kind].Stun; stun > 0 {
+		target.stun(stun)
+	}
+	if chill := TowerTypes[t.Kind].Chill; chill > 0 {
+		target.chill(chill, TowerTypes[t.Kind].ChillTicks)
+	}
+	if damage > 0 {
+		g.damageEnemy(target, damage, t)
+	}
+	if TowerTypes[t.Kind].Splash > 0 {
+		g.splash(t, target, damage)
+	}
+}
+
+// CanTarget returns true if e is alive, in range, and visible to the tower
+func (g *Game) CanTarget(t *Tower, stats TowerStats, e *Enemy) bool {
+	if e == nil || e.HP <= 0 || e.Burrowed > 0 {
+		return false
+	}
+	if g.SkipDoomed && e.doomed() {
+		return false // Already dead once what's in flight lands
+	}
+	cx, cy := CellCenter(t.X, t.Y)
+	if math.Hypot(e.X-cx, e.Y-cy) > stats.Range {
+		return false
+	}
+	return g.canSee(t.X, t.Y, e.X, e.Y)
+}
+
+// updateLasers decrements laser TTL and removes expired ones
+func (g *Game) updateLasers() {
+	alive := g.Lasers[:0] // Compacted in place
+	for _, l := range g.Lasers {
+		if l.TTL > 0 {
+			l.TTL--
+		} else {
+			l.Trail--
+		}
+		if l.TTL > 0 || l.Trail > 0 {
+			alive = append(alive, l)
+		} else {
+			g.freeLasers = append(g.freeLasers, l)
+		}
+	}
+	clear(g.Lasers[len(alive):])
+	g.Lasers = alive
+}
+
+// AddFloatingText shows text rising from a pixel position
+func (g *Game) AddFloatingText(x, y float64, text string) {
+	g.FloatTexts = append(g.FloatTexts, &FloatingText{X: x, Y: y, Text: text, TTL: FloatTextTTL})
+}
+
+// updateFloatingTexts drifts floating texts upward and removes expired ones
+func (g *Game) updateFloatingTexts() {
+	alive := g.FloatTexts[:0] // Compacted in place
+	for _, f := range g.FloatTexts {
+		f.TTL--
+		f.Y -= FloatTextRise
+		if f.TTL > 0 {
+			alive = append(alive, f)
+		}
+	}
+	clear(g.FloatTexts[len(alive):])
+	g.FloatTexts = alive
+}
+
+// Input is what the player does on the board during one step
+type Input struct {
+	Cell    Point     // Cell under the pointer
+	OnBoard bool      // The pointer is over the board, so Cell means something
+	Build   bool      // Build (or queue) a tower of Kind on Cell
+	Sell    bool      // Sell the tower on Cell
+	Cancel  bool      // Cancel a placement queued on Cell
+	Kind    TowerKind // Kind Build places
+	Hold    bool      // Apply the input without advancing time
+}
+
+// Step applies a step's input, then advances the simulation one tick unless
+// the input holds time still. Once the game is over it does nothing.
+func (g *Game) Step(in Input) {
+	if g.State != StatePlaying {
+		return
+	}
+	g.applyInput(in)
+	if !in.Hold {
+		g.step()
+	}
+	g.finishIfOver()
+}
+
+// applyInput builds, sells, or cancels on the input's cell, updating the
+// paths if the grid changed
+func (g *Game) applyInput(in Input) {
+	x, y := in.Cell.X, in.Cell.Y
+	if !in.OnBoard || !g.InGrid(x, y) {
+		return
+	}
+	tile := g.Grid[y][x]
+	gridChanged := false
+	var blocked []Point // Cells that became obstacles
+	opened := false     // A cell became walkable
+
+	// Build only on ground, if affordable
+	if in.Build && tile == TileGround {
+		if g.BuildQueueEnabled {
+			g.queueBuild(x, y, in.Kind)
+		} else if g.addTower(x, y, in.Kind) {
+			gridChanged = true
+			blocked = append(blocked, in.Cell)
+		}
+	}
+	switch {
+	case in.Cancel && tile == TileGround:
+		g.cancelBuild(x, y)
+	case in.Sell && tile == TileTower:
+		g.RemoveTower(x, y)
+		gridChanged = true
+		opened = true
+	}
+
+	// Recalculate paths if grid changed
+	if gridChanged {
+		g.OnGridChanged(blocked, opened)
+		if !opened {
+			g.rewardMazing(x, y)
+		}
+	}
+}
+
+// step advances the simulation by one tick
+func (g *Game) step() {
+	// Advance the game clock
+	g.Tick++
+
+	// Wave spawning logic
+	g.updateWave()
+
+	// Move enemies
+	g.updateEnemies()
+	g.updateFormations()
+
+	// Queued placements that can now be paid for
+	g.updateBuildQueue()
+
+	// Tower targeting and shooting
+	g.updateTowers()
+	g.updateProjectiles()
+	g.updateTraps()
+	g.updatePickups()
+	g.updateKillZone()
+
+	// Update laser visuals
+	g.updateLasers()
+	g.updateExplosions()
+	g.updateFloatingTexts()
+	if g.MegaFlash > 0 {
+		g.MegaFlash--
+	}
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+// newTestGame starts a game on a map given as map file rows
+func newTestGame(t testing.TB, rows ...string) *Game {
+	t.Helper()
+	m, err := ParseMap(strings.Join(rows, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newGameFromMap(m)
+}
+
+// openField is a walled room with the spawn and base at either end of the
+// middle row
+var openField = []string{
+	"############",
+	"#..........#",
+	"#..........#",
+	"#S........B#",
+	"#..........#",
+	"#..........#",
+	"############",
+}
+
+// buildTower places a finished tower of a kind, paying for it and updating
+// every path as a click would
+func buildTower(t testing.TB, g *Game, x, y int, kind TowerKind) *Tower {
+	t.Helper()
+	g.Resources = max(g.Resources, TowerTypes[kind].Cost)
+	if !g.addTower(x, y, kind) {
+		t.Fatalf("couldn't build at (%d, %d)", x, y)
+	}
+	g.OnGridChanged([]Point{{X: x, Y: y}}, false)
+	tower := g.Towers[len(g.Towers)-1]
+	tower.BuildTimer = 0
+	return tower
+}
+
+func TestRerouteSkipsEnemiesWhosePathAvoidsTheChange(t *testing.T) {
+	g := newTestGame(t, openField...)
+	g.spawnEnemy(EnemyNormal)
+	e := g.Enemies[0]
+	before := e.Path
+
+	buildTower(t, g, 5, 1, KindBasic) // Two rows above the route
+
+	if &e.Path[0] != &before[0] || len(e.Path) != len(before) {
+		t.Errorf("enemy was rerouted by a tower off its path")
+	}
+}
+
+func TestRerouteUpdatesEnemiesWhosePathCrossesTheChange(t *testing.T) {
+	g := newTestGame(t, openField...)
+	g.spawnEnemy(EnemyNormal)
+	e := g.Enemies[0]
+	blocked := e.Path[len(e.Path)/2]
+
+	buildTower(t, g, blocked.X, blocked.Y, KindBasic)
+
+	for _, p := range e.Path[e.PathIndex:] {
+		if p == blocked {
+			t.Fatalf("enemy still routed through the new tower at %v", blocked)
+		}
+	}
+}
+
+// checkRoute fails the test unless path runs from start to goal in steps of
+// one cell, every one of them walkable
+func checkRoute(t *testing.T, g *Game, path []Point, start, goal Point) {
+	t.Helper()
+	if len(path) == 0 {
+		t.Fatalf("no route from %v to %v", start, goal)
+	}
+	if path[0] != start || path[len(path)-1] != goal {
+		t.Fatalf("route runs %v to %v, want %v to %v", path[0], path[len(path)-1], start, goal)
+	}
+	for i, p := range path {
+		if !g.isWalkable(p.X, p.Y) {
+			t.Fatalf("route steps onto unwalkable %v", p)
+		}
+		if i > 0 && heuristic(path[i-1], p) != 1 {
+			t.Fatalf("route jumps from %v to %v", path[i-1], p)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package game
+
+// The grid's size is set when a game starts, so maps needn't all be the
+// default GridWidth by GridHeight.
+
+// newCells returns a width by height grid of zero values
+func newCells[T any](width, height int) [][]T {
+	cells := make([][]T, height)
+	for y := range cells {
+		cells[y] = make([]T, width)
+	}
+	return cells
+}
+
+// rectangular returns true if a grid's rows are all the same width, and it's
+// at least MinGridSize each way
+func rectangular[T any](cells [][]T) bool {
+	if len(cells) < MinGridSize || len(cells[0]) < MinGridSize {
+		return false
+	}
+	for _, row := range cells {
+		if len(row) != len(cells[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// GridWidth returns the number of columns in the grid
+func (g *Game) GridWidth() int {
+	return len(g.Grid[0])
+}
+
+// GridHeight returns the number of rows in the grid
+func (g *Game) GridHeight() int {
+	return len(g.Grid)
+}
+
+// InGrid returns true if a cell is on the grid
+func (g *Game) InGrid(x, y int) bool {
+	return x >= 0 && x < g.GridWidth() && y >= 0 && y < g.GridHeight()
+}
+
+// WorldWidth returns the full grid's width in pixels
+func (g *Game) WorldWidth() int {
+	return g.GridWidth() * CellSize
+}
+
+// WorldHeight returns the full grid's height in pixels
+func (g *Game) WorldHeight() int {
+	return g.GridHeight() * CellSize
+}
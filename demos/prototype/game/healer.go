@@ -0,0 +1,31 @@
+package game
+
+import "math"
+
+const (
+	HealRadius = 2 * CellSize // Pixels around a healer that its allies get healed
+	HealRate   = 0.3          // HP a healer restores to each wounded ally per tick
+)
+
+// healEnemies has every living healer restore HealRate HP to each other
+// wounded, living enemy within HealRadius, up to its kind's full health. It
+// runs before updateEnemies culls the dead and skips them, so nothing killed
+// this tick comes back.
+func (g *Game) healEnemies() {
+	for _, h := range g.Enemies {
+		if !EnemyTypes[h.Kind].Heals || h.HP <= 0 {
+			continue
+		}
+		for _, e := range g.Enemies {
+			if Heals(h, e) {
+				e.HP = math.Min(e.HP+HealRate, MaxHP(e.Kind))
+			}
+		}
+	}
+}
+
+// Heals returns true if a healer is healing an enemy: another living,
+// wounded one within HealRadius
+func Heals(h, e *Enemy) bool {
+	return e != h && e.HP > 0 && e.HP < MaxHP(e.Kind) && math.Hypot(e.X-h.X, e.Y-h.Y) <= HealRadius
+}
@@ -0,0 +1,41 @@
+package game
+
+import "math"
+
+const (
+	KillZoneBonus     = 0.5          // Extra damage fraction for tower hits inside the zone
+	KillZoneDuration  = 8 * TPS      // Ticks a kill zone lasts
+	KillZoneCooldown  = 45 * TPS     // Ticks from calling a kill zone until the next can be
+	KillZoneMinRadius = CellSize     // Pixels
+	KillZoneMaxRadius = 3 * CellSize // Pixels
+)
+
+// KillZone is a circle of the board where towers hit harder for a while
+type KillZone struct {
+	X, Y, Radius float64 // World pixels
+	Bonus        float64 // Extra damage fraction
+	TTL          int     // Ticks left
+}
+
+// updateKillZone counts down the active zone and the cooldown
+func (g *Game) updateKillZone() {
+	if g.ZoneCooldown > 0 {
+		g.ZoneCooldown--
+	}
+	if g.KillZone == nil {
+		return
+	}
+	g.KillZone.TTL--
+	if g.KillZone.TTL <= 0 {
+		g.KillZone = nil
+	}
+}
+
+// killZoneFactor returns the damage multiplier for a tower hit at a position
+func (g *Game) killZoneFactor(x, y float64) float64 {
+	z := g.KillZone
+	if z == nil || math.Hypot(x-z.X, y-z.Y) > z.Radius {
+		return 1
+	}
+	return 1 + z.Bonus
+}
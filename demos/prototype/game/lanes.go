@@ -0,0 +1,105 @@
+package game
+
+import "math"
+
+// A map can have several spawns and bases. Each spawn is a lane: its path
+// runs to whichever base is cheapest to reach from it, and enemies enter the
+// lanes in turn. Reaching any base loses the game.
+
+// pathToBase returns the cheapest path from a cell to any base, or nil if
+// none can be reached. explored is passed on to searchPath.
+func (g *Game) pathToBase(from Point, explored *[]Point) []Point {
+	return g.pathToAny(from, g.Bases, explored)
+}
+
+// pathToAny returns the cheapest path from a cell to any of bases, or nil
+// if none can be reached. explored is passed on to searchPath.
+func (g *Game) pathToAny(from Point, bases []Point, explored *[]Point) []Point {
+	var best []Point
+	bestCost := 0
+	for _, b := range bases {
+		path := g.searchPath(from, b, explored)
+		if path == nil {
+			continue
+		}
+		if cost := g.routeCost(path); best == nil || cost < bestCost {
+			best, bestCost = path, cost
+		}
+	}
+	return best
+}
+
+// routeCost returns the total A* step cost of walking a path
+func (g *Game) routeCost(path []Point) int {
+	cost := 0
+	for _, p := range path[1:] {
+		cost += g.stepCost(p)
+	}
+	return cost
+}
+
+// everyLaneOpen returns true if every spawn can reach a base
+func (g *Game) everyLaneOpen() bool {
+	for _, s := range g.Spawns {
+		if g.pathToBase(s, nil) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// pathCells returns every cell on any lane's path, each once
+func (g *Game) pathCells() []Point {
+	if len(g.Paths) == 1 {
+		return g.Paths[0]
+	}
+	seen := make(map[Point]bool)
+	var cells []Point
+	for _, path := range g.Paths {
+		for _, p := range path {
+			if !seen[p] {
+				seen[p] = true
+				cells = append(cells, p)
+			}
+		}
+	}
+	return cells
+}
+
+// LaneLengths returns the steps of the shortest and longest lane paths,
+// ignoring blocked lanes (both 0 if every lane is blocked)
+func (g *Game) LaneLengths() (shortest, longest int) {
+	for _, path := range g.Paths {
+		if len(path) == 0 {
+			continue
+		}
+		n := len(path) - 1
+		if shortest == 0 || n < shortest {
+			shortest = n
+		}
+		longest = max(longest, n)
+	}
+	return shortest, longest
+}
+
+// nearestBase returns the one of bases closest to a cell as the crow flies
+func nearestBase(bases []Point, from Point) Point {
+	best := bases[0]
+	for _, b := range bases[1:] {
+		if math.Hypot(float64(b.X-from.X), float64(b.Y-from.Y)) < math.Hypot(float64(best.X-from.X), float64(best.Y-from.Y)) {
+			best = b
+		}
+	}
+	return best
+}
+
+// NearestPoint returns the point in points closest to p, for changing it in place
+func NearestPoint(points []Point, p Point) *Point {
+	best := &points[0]
+	for i := range points {
+		if heuristic(points[i], p) < heuristic(*best, p) {
+			best = &points[i]
+		}
+	}
+	return best
+}
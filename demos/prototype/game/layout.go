@@ -0,0 +1,11 @@
+package game
+
+// towerLayout returns where each tower stands and what kind it is, in the
+// order they were built
+func (g *Game) towerLayout() []QueuedBuild {
+	layout := make([]QueuedBuild, 0, len(g.Towers))
+	for _, t := range g.Towers {
+		layout = append(layout, QueuedBuild{Cell: Point{X: t.X, Y: t.Y}, Kind: t.Kind})
+	}
+	return layout
+}
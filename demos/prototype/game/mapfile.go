@@ -0,0 +1,135 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Map files are plain text, one line per row and one character per tile.
+// Towers aren't part of a map; their cells are saved as ground. Void cells,
+// written as _, cut holes in the board or give it an irregular outline.
+
+const CustomMapName = "custom" // Map the editor saves to and the main menu offers
+
+// tileChars are the characters tiles are written as in map files
+var tileChars = map[TileType]byte{
+	TileGround: '.',
+	TileWall:   '#',
+	TileSpawn:  'S',
+	TileBase:   'B',
+	TileMud:    '~',
+	TileRoad:   '=',
+	TileVoid:   '_',
+	TileTower:  '.',
+}
+
+// mapPath returns the file a named map is kept in
+func mapPath(name string) (string, error) {
+	return ConfigPath(filepath.Join("maps", name+".txt"))
+}
+
+// SaveMap writes the board's tiles, spawn, and base to a map file
+func (g *Game) SaveMap(path string) error {
+	var b bytes.Buffer
+	for _, row := range g.Grid {
+		for _, tile := range row {
+			b.WriteByte(tileChars[tile])
+		}
+		b.WriteByte('\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// LoadMap starts a new game on the map in a file
+func LoadMap(path string) (*Game, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := ParseMap(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return newGameFromMap(m), nil
+}
+
+// ParseMap reads a map file's contents. Its rows must all be the same width,
+// it must be at least MinGridSize each way, and it must have at least one
+// spawn and one base. Each spawn is a lane.
+func ParseMap(text string) (*Map, error) {
+	chars := make(map[byte]TileType)
+	for tile, c := range tileChars {
+		if tile != TileTower {
+			chars[c] = tile
+		}
+	}
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for y := range lines {
+		lines[y] = strings.TrimSuffix(lines[y], "\r")
+	}
+	width, height := len(lines[0]), len(lines)
+	if width < MinGridSize || height < MinGridSize {
+		return nil, fmt.Errorf("map is %dx%d, smaller than the %dx%d minimum", width, height, MinGridSize, MinGridSize)
+	}
+	m := &Map{Tiles: newCells[TileType](width, height)}
+	for y, line := range lines {
+		if len(line) != width {
+			return nil, fmt.Errorf("row %d is %d tiles wide, but row 1 is %d", y+1, len(line), width)
+		}
+		for x := range width {
+			tile, ok := chars[line[x]]
+			if !ok {
+				return nil, fmt.Errorf("row %d column %d: unknown tile %q", y+1, x+1, line[x])
+			}
+			switch tile {
+			case TileSpawn:
+				m.addSpawn(Point{X: x, Y: y})
+			case TileBase:
+				m.addBase(Point{X: x, Y: y})
+			default:
+				m.Tiles[y][x] = tile
+			}
+		}
+	}
+	if len(m.Spawns) == 0 || len(m.Bases) == 0 {
+		return nil, fmt.Errorf("%d spawns and %d bases, want at least one of each", len(m.Spawns), len(m.Bases))
+	}
+	return m, nil
+}
+
+// SaveCustomMap saves the board as the custom map
+func (g *Game) SaveCustomMap() error {
+	path, err := mapPath(CustomMapName)
+	if err != nil {
+		return err
+	}
+	return g.SaveMap(path)
+}
+
+// NewNamedGame starts a game on a saved map, or on the built-in layout if
+// there's no map by that name
+func NewNamedGame(name string) *Game {
+	path, err := mapPath(name)
+	if err != nil {
+		log.Printf("finding map %q: %v", name, err)
+		return NewGame(GridWidth, GridHeight)
+	}
+	g, err := LoadMap(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("loading map %q (using the built-in layout): %v", name, err)
+		}
+		return NewGame(GridWidth, GridHeight)
+	}
+	g.MapName = name
+	return g
+}
@@ -0,0 +1,253 @@
+package game
+
+import (
+	"log"
+	"math/rand/v2"
+)
+
+// Map is a level layout: the tile grid, spawn and base positions (one lane
+// per spawn), and any towers the player starts with
+type Map struct {
+	Tiles  [][]TileType // Rows of cells
+	Spawns []Point
+	Bases  []Point
+	Towers []TowerPlacement
+}
+
+// TowerPlacement is a tower that starts on the map, free of charge
+type TowerPlacement struct {
+	Cell Point
+	Kind TowerKind
+}
+
+// MapGenOptions tunes procedural map generation
+type MapGenOptions struct {
+	WallDensity    float64 // Target fraction of interior cells that are walls
+	MudDensity     float64 // Target fraction of interior cells that are mud
+	RoadDensity    float64 // Target fraction of interior cells that are road
+	MaxClusterSize int     // Largest wall (or terrain) cluster grown from one seed cell
+}
+
+// DefaultMapGen is used by GenerateMap
+var DefaultMapGen = MapGenOptions{
+	WallDensity:    0.15,
+	MudDensity:     0.05,
+	RoadDensity:    0.05,
+	MaxClusterSize: 8,
+}
+
+// defaultMap returns the built-in layout on a grid of the given size
+func defaultMap(width, height int) *Map {
+	m := newWalledMap(width, height)
+
+	// Place base (what we're defending) - bottom center
+	m.addBase(Point{X: width / 2, Y: height - 2})
+
+	// Place spawn point - top center
+	m.addSpawn(Point{X: width / 2, Y: 1})
+
+	// Add some interior walls for interest
+	for y := 3; y <= height/2; y++ {
+		m.Tiles[y][width/4] = TileWall
+		m.Tiles[y][width-1-width/4] = TileWall
+	}
+	return m
+}
+
+// tutorialMap returns the default layout with a starter funnel already built,
+// so new players can watch towers work before spending anything
+func tutorialMap() *Map {
+	m := defaultMap(GridWidth, GridHeight)
+	for _, x := range []int{7, 9, 11, 13} {
+		m.Towers = append(m.Towers, TowerPlacement{Cell: Point{X: x, Y: 6}, Kind: KindBasic})
+	}
+	return m
+}
+
+// newWalledMap returns a map of ground surrounded by walls
+func newWalledMap(width, height int) *Map {
+	m := &Map{Tiles: newCells[TileType](width, height)}
+
+	// Fill with ground
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			m.Tiles[y][x] = TileGround
+		}
+	}
+
+	// Add some walls around edges
+	for x := 0; x < width; x++ {
+		m.Tiles[0][x] = TileWall
+		m.Tiles[height-1][x] = TileWall
+	}
+	for y := 0; y < height; y++ {
+		m.Tiles[y][0] = TileWall
+		m.Tiles[y][width-1] = TileWall
+	}
+	return m
+}
+
+// width returns the number of columns in the map
+func (m *Map) width() int {
+	return len(m.Tiles[0])
+}
+
+// height returns the number of rows in the map
+func (m *Map) height() int {
+	return len(m.Tiles)
+}
+
+// addSpawn places a spawn tile
+func (m *Map) addSpawn(p Point) {
+	m.Spawns = append(m.Spawns, p)
+	m.Tiles[p.Y][p.X] = TileSpawn
+}
+
+// addBase places a base tile
+func (m *Map) addBase(p Point) {
+	m.Bases = append(m.Bases, p)
+	m.Tiles[p.Y][p.X] = TileBase
+}
+
+// GenerateMap produces a random map that always has a route from spawn to base
+func GenerateMap(seed int64) *Map {
+	return GenerateMapWith(seed, DefaultMapGen)
+}
+
+// GenerateMapWith is GenerateMap with explicit tuning
+func GenerateMapWith(seed int64, opts MapGenOptions) *Map {
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	m := newWalledMap(GridWidth, GridHeight)
+
+	// Spawn somewhere along the top, base somewhere along the bottom
+	m.addSpawn(Point{X: 1 + rng.IntN(GridWidth-2), Y: 1})
+	m.addBase(Point{X: 1 + rng.IntN(GridWidth-2), Y: GridHeight - 2})
+
+	// Grow wall clusters until the target density is reached
+	interior := (GridWidth - 2) * (GridHeight - 2)
+	m.scatterTiles(rng, TileWall, int(opts.WallDensity*float64(interior)), opts.MaxClusterSize)
+
+	// Guarantee a route by carving a corridor if the walls cut one off
+	if (&Game{Grid: m.Tiles}).findPath(m.Spawns[0], m.Bases[0]) == nil {
+		m.carveCorridor(m.Spawns[0], m.Bases[0])
+	}
+
+	// Terrain only changes speeds, so it can't cut the route
+	m.scatterTiles(rng, TileMud, int(opts.MudDensity*float64(interior)), opts.MaxClusterSize)
+	m.scatterTiles(rng, TileRoad, int(opts.RoadDensity*float64(interior)), opts.MaxClusterSize)
+	return m
+}
+
+// placeStartingTowers builds a map's pre-placed towers without charging for
+// them. Placements off open ground, or that would cut the only route from
+// spawn to base, are skipped so a bad map stays playable.
+func (g *Game) placeStartingTowers(placements []TowerPlacement) {
+	for _, p := range placements {
+		x, y := p.Cell.X, p.Cell.Y
+		if !g.InGrid(x, y) || g.Grid[y][x] != TileGround {
+			log.Printf("skipping starting tower at (%d,%d): not open ground", x, y)
+			continue
+		}
+		g.Grid[y][x] = TileTower
+		if !g.everyLaneOpen() {
+			g.Grid[y][x] = TileGround
+			log.Printf("skipping starting tower at (%d,%d): it would block the path", x, y)
+			continue
+		}
+		g.Towers = append(g.Towers, g.newTower(x, y, p.Kind))
+	}
+}
+
+// twoLaneMap returns a layout split down the middle by a wall, with a spawn
+// and base on each side: two lanes that have to be defended separately
+func twoLaneMap() *Map {
+	m := newWalledMap(GridWidth, GridHeight)
+	for y := 1; y < GridHeight-1; y++ {
+		m.Tiles[y][GridWidth/2] = TileWall
+	}
+	m.addSpawn(Point{X: GridWidth / 4, Y: 1})
+	m.addSpawn(Point{X: GridWidth * 3 / 4, Y: 1})
+	m.addBase(Point{X: GridWidth / 4, Y: GridHeight - 2})
+	m.addBase(Point{X: GridWidth * 3 / 4, Y: GridHeight - 2})
+	for y := 4; y < GridHeight-4; y += 3 {
+		m.Tiles[y][GridWidth/4-1] = TileWall
+		m.Tiles[y][GridWidth*3/4+1] = TileWall
+	}
+	return m
+}
+
+// randomStep moves one cell in a random direction, staying off the border
+func (m *Map) randomStep(rng *rand.Rand, p Point) Point {
+	dirs := []Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+	d := dirs[rng.IntN(len(dirs))]
+	p.X = max(1, min(m.width()-2, p.X+d.X))
+	p.Y = max(1, min(m.height()-2, p.Y+d.Y))
+	return p
+}
+
+// scatterTiles turns clusters of ground into another tile by random walks,
+// until count cells have changed or it runs out of attempts
+func (m *Map) scatterTiles(rng *rand.Rand, tile TileType, count, maxCluster int) {
+	interior := (m.width() - 2) * (m.height() - 2)
+	placed := 0
+	for attempts := 0; placed < count && attempts < interior*4; attempts++ {
+		p := Point{X: 1 + rng.IntN(m.width()-2), Y: 1 + rng.IntN(m.height()-2)}
+		size := 1 + rng.IntN(max(maxCluster, 1))
+		for i := 0; i < size && placed < count; i++ {
+			if m.Tiles[p.Y][p.X] == TileGround {
+				m.Tiles[p.Y][p.X] = tile
+				placed++
+			}
+			p = m.randomStep(rng, p)
+		}
+	}
+}
+
+// carveCorridor turns walls into ground along an L-shaped route between two cells
+func (m *Map) carveCorridor(from, to Point) {
+	p := from
+	for p != to {
+		switch {
+		case p.Y != to.Y:
+			p.Y += sign(to.Y - p.Y)
+		default:
+			p.X += sign(to.X - p.X)
+		}
+		if m.Tiles[p.Y][p.X] == TileWall {
+			m.Tiles[p.Y][p.X] = TileGround
+		}
+	}
+}
+
+// sign returns -1, 0, or 1
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
+
+// NewGeneratedGame creates a new game on a procedurally generated map
+func NewGeneratedGame(seed int64) *Game {
+	g := newGameFromMap(GenerateMap(seed))
+	g.Generated = true
+	g.MapSeed = seed
+	return g
+}
+
+// NewTwoLaneGame creates a new game on the two-lane map
+func NewTwoLaneGame() *Game {
+	g := newGameFromMap(twoLaneMap())
+	g.TwoLanes = true
+	return g
+}
+
+// NewTutorialGame creates a new game on the tutorial map
+func NewTutorialGame() *Game {
+	g := newGameFromMap(tutorialMap())
+	g.Tutorial = true
+	return g
+}
@@ -0,0 +1,63 @@
+package game
+
+import "testing"
+
+func TestGeneratedMapsAlwaysHaveARoute(t *testing.T) {
+	for seed := range int64(100) {
+		g := NewGeneratedGame(seed)
+		if g.PathBlocked {
+			t.Fatalf("seed %d: path blocked", seed)
+		}
+		checkRoute(t, g, g.Paths[0], g.Spawns[0], g.Bases[0])
+	}
+}
+
+func TestRoutesGoAroundVoid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		rows []string
+	}{
+		{name: "hole", rows: []string{
+			"###########",
+			"#.........#",
+			"#...___...#",
+			"#S..___..B#",
+			"#...___...#",
+			"#.........#",
+			"###########",
+		}},
+		{name: "L-shape", rows: []string{
+			"#####______",
+			"#S..#______",
+			"#...#______",
+			"#...#######",
+			"#.........#",
+			"#........B#",
+			"###########",
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestGame(t, tc.rows...)
+			checkRoute(t, g, g.Paths[0], g.Spawns[0], g.Bases[0])
+			for _, p := range g.Paths[0] {
+				if g.Grid[p.Y][p.X] == TileVoid {
+					t.Fatalf("route crosses void at %v", p)
+				}
+			}
+
+			// Nothing can be built on void either
+			for y, row := range g.Grid {
+				for x, tile := range row {
+					if tile != TileVoid {
+						continue
+					}
+					g.Resources = 1000
+					g.Step(Input{Cell: Point{X: x, Y: y}, OnBoard: true, Build: true, Kind: KindBasic, Hold: true})
+					if g.Grid[y][x] != TileVoid || len(g.Towers) > 0 {
+						t.Fatalf("built on void at (%d, %d)", x, y)
+					}
+				}
+			}
+		})
+	}
+}
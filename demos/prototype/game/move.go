@@ -0,0 +1,35 @@
+package game
+
+const MoveFee = 5 // Flat cost of relocating a tower
+
+// CanMoveTo returns true if a tower could be moved to a cell: open ground,
+// affordable, and leaving enemies a way to the base (if they have one now)
+func (g *Game) CanMoveTo(t *Tower, x, y int) bool {
+	if g.Grid[y][x] != TileGround || g.Resources < MoveFee {
+		return false
+	}
+	if g.PathBlocked {
+		return true // Can't make it worse, and may open a way
+	}
+	g.Grid[t.Y][t.X], g.Grid[y][x] = TileGround, TileTower
+	open := g.everyLaneOpen()
+	g.Grid[t.Y][t.X], g.Grid[y][x] = TileTower, TileGround
+	return open
+}
+
+// MoveTower relocates a tower to a cell for MoveFee. It keeps everything it
+// has earned (kill stacks, fire mode, facing). Returns false, leaving it
+// where it was, if the cell isn't a valid spot.
+func (g *Game) MoveTower(t *Tower, x, y int) bool {
+	if !g.CanMoveTo(t, x, y) {
+		return false
+	}
+	g.Resources -= MoveFee
+	g.Grid[t.Y][t.X] = TileGround
+	g.Grid[y][x] = TileTower
+	t.X, t.Y = x, y
+	t.Target = nil
+	t.BeamCharge = 0
+	g.OnGridChanged([]Point{{X: x, Y: y}}, true)
+	return true
+}
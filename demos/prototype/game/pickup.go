@@ -0,0 +1,41 @@
+package game
+
+const (
+	PickupValue  = 15      // Resources a collected orb gives
+	PickupTTL    = 5 * TPS // Ticks an orb lasts before vanishing
+	PickupRadius = 10.0    // Orb radius, in world pixels
+	PickupReach  = 20.0    // How close a click must land to collect an orb
+)
+
+// Pickup is a resource orb dropped by a dying enemy, collected by clicking it
+type Pickup struct {
+	X, Y  float64 // World pixel position
+	Value int
+	TTL   int // Ticks left before it vanishes
+}
+
+// maybeDropPickup rolls a killed enemy's kind's drop chance and leaves an
+// orb where it died. The roll depends only on the game's seed and kill
+// count, so runs stay deterministic.
+func (g *Game) maybeDropPickup(e *Enemy) {
+	chance := EnemyTypes[e.Kind].DropChance
+	if !g.PickupsEnabled || chance == 0 {
+		return
+	}
+	if g.rngFor(streamPickups, g.TotalKills).Float64() >= chance {
+		return
+	}
+	g.Pickups = append(g.Pickups, Pickup{X: e.X, Y: e.Y, Value: PickupValue, TTL: PickupTTL})
+}
+
+// updatePickups ages orbs, removing the ones that have expired
+func (g *Game) updatePickups() {
+	live := g.Pickups[:0] // Compacted in place
+	for _, p := range g.Pickups {
+		p.TTL--
+		if p.TTL > 0 {
+			live = append(live, p)
+		}
+	}
+	g.Pickups = live
+}
@@ -0,0 +1,69 @@
+package game
+
+// Enemies and lasers come and go by the hundred in a big wave. Rather than
+// allocating each one and leaving the old ones to the GC, finished ones go on
+// a free list and are reset and handed out again.
+
+// newEnemy returns a zeroed enemy, reusing a released one if available. The
+// released enemy's path buffer is kept, so spawning can copy into it.
+func (g *Game) newEnemy() *Enemy {
+	n := len(g.freeEnemies)
+	if n == 0 {
+		return &Enemy{}
+	}
+	e := g.freeEnemies[n-1]
+	g.freeEnemies = g.freeEnemies[:n-1]
+	*e = Enemy{Path: e.Path[:0]}
+	return e
+}
+
+// releaseEnemy returns a removed enemy to the free list. Towers and shells
+// still aiming at it drop it, so they can't end up locked onto whatever
+// reuses it.
+func (g *Game) releaseEnemy(e *Enemy) {
+	for _, t := range g.Towers {
+		if t.Target == e {
+			t.Target = nil
+		}
+	}
+	for _, p := range g.Projectiles {
+		if p.target == e {
+			p.target = nil
+		}
+	}
+	g.freeEnemies = append(g.freeEnemies, e)
+}
+
+const (
+	TrailDuration = 3 * TPS // Ticks a shot trail takes to fade out after its tracer
+	MaxLasers     = 500     // Cap on tracers and trails on screen; the oldest go first
+)
+
+// addLaser shows a shot by a tower of the given kind from one pixel position
+// to another, reusing a faded laser if available. At MaxLasers the oldest
+// on screen is reused instead. Returns the laser, for callers to restyle.
+func (g *Game) addLaser(kind TowerKind, fromX, fromY, toX, toY float64) *Laser {
+	var l *Laser
+	if len(g.Lasers) >= MaxLasers {
+		l = g.Lasers[0]
+		copy(g.Lasers, g.Lasers[1:])
+		g.Lasers = g.Lasers[:len(g.Lasers)-1]
+	} else if n := len(g.freeLasers); n > 0 {
+		l = g.freeLasers[n-1]
+		g.freeLasers = g.freeLasers[:n-1]
+	} else {
+		l = &Laser{}
+	}
+	tt := TowerTypes[kind]
+	width := tt.LaserWidth
+	if width == 0 {
+		width = DefaultLaserWidth
+	}
+	trail := 0
+	if g.ShotTrails {
+		trail = TrailDuration
+	}
+	*l = Laser{FromX: fromX, FromY: fromY, ToX: toX, ToY: toY, TTL: g.TracerTicks, Trail: trail, Color: tt.LaserColor, Width: width}
+	g.Lasers = append(g.Lasers, l)
+	return l
+}
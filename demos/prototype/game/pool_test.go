@@ -0,0 +1,81 @@
+package game
+
+import "testing"
+
+const benchEnemies = 300 // A big wave's worth on the board at once
+
+// benchWave returns a default game with towers along the route, ready to
+// be kept topped up to benchEnemies
+func benchWave(b *testing.B) *Game {
+	b.Helper()
+	g := NewGame(GridWidth, GridHeight)
+	path := g.Paths[0]
+	for i := 2; i < len(path)-2; i += 3 {
+		p := Point{X: path[i].X + 1, Y: path[i].Y}
+		if g.Grid[p.Y][p.X] == TileGround {
+			buildTower(b, g, p.X, p.Y, KindBasic)
+		}
+	}
+	if g.PathBlocked {
+		b.Fatal("towers cut off the route")
+	}
+	return g
+}
+
+// topUp spawns enemies until benchEnemies are on the board
+func topUp(g *Game) {
+	for len(g.Enemies) < benchEnemies {
+		g.spawnEnemy(EnemyNormal)
+	}
+}
+
+// BenchmarkStep runs simulation ticks with a steady benchEnemies on the
+// board, with the free lists in use and with them emptied every tick as if
+// there were no pooling
+func BenchmarkStep(b *testing.B) {
+	for _, pooled := range []bool{true, false} {
+		name := "pooled"
+		if !pooled {
+			name = "unpooled"
+		}
+		b.Run(name, func(b *testing.B) {
+			g := benchWave(b)
+			for range TPS { // Warm the pools
+				topUp(g)
+				g.step()
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				if !pooled {
+					g.freeEnemies, g.freeLasers = nil, nil
+				}
+				topUp(g)
+				g.step()
+			}
+		})
+	}
+}
+
+// BenchmarkUpdateEnemies moves benchEnemies along the route each tick
+func BenchmarkUpdateEnemies(b *testing.B) {
+	g := benchWave(b)
+	topUp(g)
+	b.ReportAllocs()
+	for b.Loop() {
+		topUp(g)
+		g.updateEnemies()
+	}
+}
+
+// BenchmarkUpdateLasers fades a screen full of lasers, replacing each as it
+// goes
+func BenchmarkUpdateLasers(b *testing.B) {
+	g := benchWave(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		for len(g.Lasers) < MaxLasers {
+			g.addLaser(KindBasic, 0, 0, CellSize, CellSize)
+		}
+		g.updateLasers()
+	}
+}
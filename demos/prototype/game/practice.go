@@ -0,0 +1,24 @@
+package game
+
+// NewPracticeGame starts on the default map at a chosen wave, skipping the
+// earlier ones, with the resources a run would have earned clearing them.
+// Practice runs aren't scored.
+func NewPracticeGame(wave int) *Game {
+	g := NewGame(GridWidth, GridHeight)
+	g.PracticeWave = wave
+	g.CurrentWave = wave
+	g.clearedWave = wave - 1
+	g.EnemiesThisWave = g.WaveSize(wave)
+	g.Resources = g.practiceBudget(wave)
+	return g
+}
+
+// practiceBudget returns the starting resources plus every kill reward and
+// wave clear bonus (without clean wave extras) of the waves before wave
+func (g *Game) practiceBudget(wave int) int {
+	budget := StartingResource
+	for w := 1; w < wave; w++ {
+		budget += g.WaveSize(w)*KillReward + WaveClearBonus + WaveClearPerWave*w
+	}
+	return budget
+}
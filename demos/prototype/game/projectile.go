@@ -0,0 +1,116 @@
+package game
+
+import "math"
+
+const (
+	ProjectileRadius     = 3.0          // Drawn radius in pixels
+	ProjectileSeekRadius = 2 * CellSize // How far a shell whose target died looks for another
+)
+
+// Projectile is a shell in flight from a tower to its target. It leads the
+// target, heading for where the two will meet, and hits on contact.
+type Projectile struct {
+	X, Y   float64 // Position in pixels
+	VX, VY float64 // Velocity in pixels per tick, re-aimed every tick
+	Damage float64 // Dealt on contact, along with the tower's on-hit effects
+	target *Enemy  // nil once its target is gone, until it finds another
+	Tower  *Tower  // Fired it; credited with the hit
+}
+
+// fireProjectile launches a shell from a tower at a target. The damage
+// counts as incoming on the target until the shell lands or loses it.
+func (g *Game) fireProjectile(t *Tower, target *Enemy, damage float64) {
+	x, y := CellCenter(t.X, t.Y)
+	p := &Projectile{X: x, Y: y, Damage: damage, Tower: t}
+	p.aimAt(target)
+	p.VX, p.VY = g.leadVelocity(x, y, target, TowerTypes[t.Kind].ShellSpeed)
+	g.Projectiles = append(g.Projectiles, p)
+}
+
+// leadVelocity returns the velocity a shell at a pixel position needs to
+// meet an enemy, assuming the enemy keeps its current velocity. If the
+// enemy is too fast to catch, it aims straight at the enemy instead.
+func (g *Game) leadVelocity(x, y float64, e *Enemy, speed float64) (vx, vy float64) {
+	ex, ey := g.VelocityAt(e)
+	dx, dy := e.X-x, e.Y-y
+	// Solve |d + v*t| = speed*t for the earliest positive time t
+	a := ex*ex + ey*ey - speed*speed
+	b := 2 * (dx*ex + dy*ey)
+	c := dx*dx + dy*dy
+	t := -1.0
+	if math.Abs(a) < 1e-9 {
+		if b < 0 {
+			t = -c / b
+		}
+	} else if disc := b*b - 4*a*c; disc >= 0 {
+		root := math.Sqrt(disc)
+		t0, t1 := (-b-root)/(2*a), (-b+root)/(2*a)
+		t = math.Min(t0, t1)
+		if t <= 0 {
+			t = math.Max(t0, t1)
+		}
+	}
+	if t > 0 {
+		dx, dy = dx+ex*t, dy+ey*t
+	}
+	ux, uy := Unit(dx, dy)
+	return ux * speed, uy * speed
+}
+
+// aimAt switches a shell to a new target (or none), moving its incoming
+// damage along with it
+func (p *Projectile) aimAt(e *Enemy) {
+	if p.target != nil {
+		p.target.incoming -= p.Damage
+	}
+	p.target = e
+	if e != nil {
+		e.incoming += p.Damage
+	}
+}
+
+// updateProjectiles moves each shell toward where it'll meet its target and
+// lands the ones that reach it. A shell whose target died or burrowed seeks the nearest
+// other enemy within ProjectileSeekRadius, and fizzles if there's none.
+func (g *Game) updateProjectiles() {
+	alive := g.Projectiles[:0]
+	for _, p := range g.Projectiles {
+		if e := p.target; e == nil || e.HP <= 0 || e.Burrowed > 0 {
+			p.aimAt(g.nearestEnemy(p.X, p.Y, ProjectileSeekRadius))
+		}
+		e := p.target
+		if e == nil {
+			continue // Fizzled
+		}
+		speed := TowerTypes[p.Tower.Kind].ShellSpeed
+		dx, dy := e.X-p.X, e.Y-p.Y
+		dist := math.Hypot(dx, dy)
+		if dist <= speed+e.Radius() {
+			p.aimAt(nil)
+			g.hitEnemy(p.Tower, e, p.Damage)
+			continue
+		}
+		p.VX, p.VY = g.leadVelocity(p.X, p.Y, e, speed)
+		p.X += p.VX
+		p.Y += p.VY
+		alive = append(alive, p)
+	}
+	clear(g.Projectiles[len(alive):])
+	g.Projectiles = alive
+}
+
+// nearestEnemy returns the closest living, surfaced enemy within radius of a
+// pixel position, or nil. Ties go to the enemy that spawned first.
+func (g *Game) nearestEnemy(x, y, radius float64) *Enemy {
+	var nearest *Enemy
+	best := radius
+	for _, e := range g.Enemies {
+		if e.HP <= 0 || e.Burrowed > 0 {
+			continue
+		}
+		if d := math.Hypot(e.X-x, e.Y-y); d <= best && (nearest == nil || d < best) {
+			nearest, best = e, d
+		}
+	}
+	return nearest
+}
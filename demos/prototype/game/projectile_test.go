@@ -0,0 +1,147 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSkipDoomedRetargetsPastAShellInFlight(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		skipDoomed bool
+		wantDoomed bool // The second cannon also aims at the doomed enemy
+	}{
+		{name: "on", skipDoomed: true, wantDoomed: false},
+		{name: "off", skipDoomed: false, wantDoomed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestGame(t, openField...)
+			g.SkipDoomed = tc.skipDoomed
+			first := buildTower(t, g, 5, 1, KindCannon)
+			second := buildTower(t, g, 6, 1, KindCannon)
+			doomed := placeEnemy(g, 5, 3)
+			other := placeEnemy(g, 6, 3)
+			doomed.HP = 1 // One shell finishes it
+			doomed.PathIndex = 3
+
+			g.updateTowers()
+
+			if first.Target != doomed || len(g.Projectiles) != 2 {
+				t.Fatalf("first cannon didn't shell the enemy furthest along")
+			}
+			want := other
+			if tc.wantDoomed {
+				want = doomed
+			}
+			if second.Target != want {
+				t.Errorf("second cannon aimed at the doomed enemy: %v, want %v", second.Target == doomed, tc.wantDoomed)
+			}
+		})
+	}
+}
+
+func TestShellRetargetsWhenItsTargetDies(t *testing.T) {
+	g := newTestGame(t, openField...)
+	cannon := buildTower(t, g, 5, 1, KindCannon)
+	target := placeEnemy(g, 5, 4)
+	nearby := placeEnemy(g, 6, 2) // Within ProjectileSeekRadius of the shell
+	g.fireProjectile(cannon, target, TowerTypes[KindCannon].Damage)
+	shell := g.Projectiles[0]
+
+	target.HP = 0
+	g.updateProjectiles()
+
+	if shell.target != nearby {
+		t.Fatalf("shell didn't seek the enemy near it")
+	}
+	if target.incoming != 0 || nearby.incoming != shell.Damage {
+		t.Errorf("incoming = %v on the dead target and %v on the new one, want 0 and %v",
+			target.incoming, nearby.incoming, shell.Damage)
+	}
+}
+
+// crossingEnemy places an enemy on a cell of openField's middle row, heading
+// straight for the base
+func crossingEnemy(g *Game, x int) *Enemy {
+	e := placeEnemy(g, x, 3)
+	e.Path = []Point{g.Spawns[0], g.Bases[0]}
+	e.PathIndex = 1
+	return e
+}
+
+func TestLeadingAccountsForSlow(t *testing.T) {
+	const shellSpeed = 5.0
+	for _, tc := range []struct {
+		name string
+		slow float64
+	}{
+		{name: "full speed", slow: 0},
+		{name: "chilled", slow: 0.5},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestGame(t, openField...)
+			e := crossingEnemy(g, 5)
+			e.chill(tc.slow, TPS)
+			x, y := CellCenter(5, 1) // Right above the enemy
+
+			vx, vy := g.leadVelocity(x, y, e, shellSpeed)
+
+			// Meeting an enemy crossing the line of fire, the shell keeps
+			// pace with it sideways while it closes the gap
+			want := EnemySpeed * (1 - tc.slow)
+			if math.Abs(vx-want) > 1e-9 {
+				t.Errorf("shell's sideways speed = %v, want %v", vx, want)
+			}
+			if math.Abs(math.Hypot(vx, vy)-shellSpeed) > 1e-9 {
+				t.Errorf("shell speed = %v, want %v", math.Hypot(vx, vy), shellSpeed)
+			}
+		})
+	}
+}
+
+func TestShellsConnectWithCrossingEnemies(t *testing.T) {
+	g := newTestGame(t, openField...)
+	cannon := buildTower(t, g, 5, 1, KindCannon)
+	e := crossingEnemy(g, 3)
+	e.HP = 1e6 // Survives the hit
+	hp := e.HP
+	g.fireProjectile(cannon, e, TowerTypes[KindCannon].Damage)
+
+	// Flying its first heading unchanged, the shell still meets the enemy
+	shell := *g.Projectiles[0]
+	ex, ey := e.X, e.Y
+	vx, vy := g.VelocityAt(e)
+	met := false
+	for range 5 * TPS {
+		shell.X, shell.Y = shell.X+shell.VX, shell.Y+shell.VY
+		ex, ey = ex+vx, ey+vy
+		met = met || math.Hypot(ex-shell.X, ey-shell.Y) <= e.Radius()
+	}
+	if !met {
+		t.Errorf("shell's first heading misses the crossing enemy")
+	}
+
+	for tick := 0; len(g.Projectiles) > 0; tick++ {
+		if tick > 5*TPS {
+			t.Fatal("shell never landed")
+		}
+		g.updateEnemies()
+		g.updateProjectiles()
+	}
+	if e.HP >= hp {
+		t.Errorf("shell fizzled without hitting the crossing enemy")
+	}
+}
+
+func TestLeadingAimsStraightAtEnemiesTooFastToCatch(t *testing.T) {
+	g := newTestGame(t, openField...)
+	e := crossingEnemy(g, 5)
+	x, y := CellCenter(5, 1) // Right above the enemy
+	slow := EnemySpeed / 2
+
+	vx, vy := g.leadVelocity(x, y, e, slow)
+
+	if math.Abs(vx) > 1e-9 || math.Abs(vy-slow) > 1e-9 {
+		t.Errorf("shell velocity = (%v, %v), want straight down at %v", vx, vy, slow)
+	}
+}
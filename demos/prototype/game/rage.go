@@ -0,0 +1,21 @@
+package game
+
+// Once a wave has finished spawning, its last few survivors rage: they move
+// (and, in siege mode, hit towers) harder the fewer of them are left, so a
+// wave doesn't peter out into a few stragglers.
+
+const (
+	RageSurvivors = 3   // Rage starts when this many of a fully spawned wave are left
+	RageMaxBonus  = 0.5 // Speed and attack bonus for the wave's very last enemy
+)
+
+// Rage returns the current speed and attack bonus for every enemy on the
+// board: 0 until the wave is down to RageSurvivors, then rising to
+// RageMaxBonus as they die
+func (g *Game) Rage() float64 {
+	n := len(g.Enemies)
+	if !g.RageEnabled || g.EnemiesThisWave > 0 || n == 0 || n > RageSurvivors {
+		return 0
+	}
+	return RageMaxBonus * float64(RageSurvivors-n+1) / RageSurvivors
+}
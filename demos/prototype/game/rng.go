@@ -0,0 +1,44 @@
+package game
+
+import "math/rand/v2"
+
+// Random decisions in a game never share a generator. Each gets a fresh one
+// from the game's seed, a stream naming what it decides, and a counter (the
+// nth kill, the nth spawn), so a seed and the same inputs always play out the
+// same, however the decisions interleave.
+
+const EnemyHPJitter = 0.1 // Spawned enemies' HP varies by up to this fraction either way
+
+// rngStream names what a random decision is for
+type rngStream uint64
+
+const (
+	streamPickups rngStream = iota // Pickup drops, by kill count
+	streamEnemyHP                  // Spawned enemies' health, by spawn count
+)
+
+// rngFor returns the generator for the nth decision of a stream. The one
+// generator is reseeded for every decision, so spawning doesn't allocate;
+// it's only good until the next call.
+func (g *Game) rngFor(stream rngStream, n int) *rand.Rand {
+	if g.rng == nil {
+		g.rng = rand.New(&g.pcg)
+	}
+	g.pcg.Seed(g.Seed, uint64(stream)<<48|uint64(n))
+	return g.rng
+}
+
+// NewGameSeed creates a game on the built-in layout whose random decisions
+// all follow from seed
+func NewGameSeed(seed uint64) *Game {
+	g := NewGame(GridWidth, GridHeight)
+	g.Seed = seed
+	return g
+}
+
+// spawnHP returns the health of the next enemy to spawn, of a kind: its
+// kind's MaxHP, varied by up to EnemyHPJitter
+func (g *Game) spawnHP(kind EnemyKind) float64 {
+	u := g.rngFor(streamEnemyHP, g.totalSpawned).Float64()
+	return MaxHP(kind) * (1 + EnemyHPJitter*(2*u-1))
+}
@@ -0,0 +1,157 @@
+package game
+
+import "slices"
+
+// With several bases, a routing policy picks which one each enemy heads for.
+// Lane paths (drawn, covered, measured for mazing) always run to the cheapest
+// base; an enemy the policy sends elsewhere gets a route of its own.
+
+const (
+	BaseMaxHP  = 100.0 // Each base's health once bases start taking damage
+	LeakDamage = 25.0  // Base HP an enemy that gets through costs, with several bases
+)
+
+// RoutingPolicy is how enemies choose among several bases
+type RoutingPolicy int
+
+const (
+	RouteNearest    RoutingPolicy = iota // Cheapest base to reach
+	RouteWeakest                         // Base with the least HP; nearest while bases are level
+	RouteRoundRobin                      // Each base in turn, one enemy at a time
+	numRoutingPolicies
+)
+
+var routingPolicyNames = [numRoutingPolicies]string{"Nearest", "Weakest", "Round-robin"}
+
+func (p RoutingPolicy) String() string {
+	return routingPolicyNames[p]
+}
+
+// Next returns the policy after p, wrapping around
+func (p RoutingPolicy) Next() RoutingPolicy {
+	return (p + 1) % numRoutingPolicies
+}
+
+// Prev returns the policy before p, wrapping around
+func (p RoutingPolicy) Prev() RoutingPolicy {
+	return (p + numRoutingPolicies - 1) % numRoutingPolicies
+}
+
+// SetRouting switches the routing policy, turning enemies already on the
+// board toward the bases it picks
+func (g *Game) SetRouting(p RoutingPolicy) {
+	g.Routing = p
+	g.rerouteToBases()
+}
+
+// DamageBase lowers a base's HP, first giving every base BaseMaxHP if none
+// has taken damage yet. Under RouteWeakest, enemies already on the board
+// turn toward whichever base is now weakest.
+func (g *Game) DamageBase(i int, amount float64) {
+	if g.BaseHP == nil {
+		g.BaseHP = make([]float64, len(g.Bases))
+		for j := range g.BaseHP {
+			g.BaseHP[j] = BaseMaxHP
+		}
+	}
+	g.BaseHP[i] = max(g.BaseHP[i]-amount, 0)
+	if g.Routing == RouteWeakest {
+		g.rerouteToBases()
+	}
+}
+
+// leakedBase returns the index of the base an enemy's route ends at, or -1
+// if it has no route
+func (g *Game) leakedBase(e *Enemy) int {
+	if len(e.Path) == 0 {
+		return -1
+	}
+	return slices.Index(g.Bases, e.Path[len(e.Path)-1])
+}
+
+// chargeBases charges each leak to the base it reached. With one base any
+// leak loses; with several, each costs that base LeakDamage and the game is
+// lost once a base has none left.
+func (g *Game) chargeBases(leaked []int) {
+	for _, i := range leaked {
+		if len(g.Bases) < 2 || i < 0 {
+			g.State = StateLost
+			return
+		}
+		g.DamageBase(i, LeakDamage)
+		if g.BaseHP[i] == 0 {
+			g.State = StateLost
+			return
+		}
+	}
+}
+
+// weakestBases returns the bases with the least HP: every base until one
+// has taken damage
+func (g *Game) weakestBases() []Point {
+	if g.BaseHP == nil {
+		return g.Bases
+	}
+	lowest := g.BaseHP[0]
+	for _, hp := range g.BaseHP[1:] {
+		lowest = min(lowest, hp)
+	}
+	var weakest []Point
+	for i, hp := range g.BaseHP {
+		if hp == lowest {
+			weakest = append(weakest, g.Bases[i])
+		}
+	}
+	return weakest
+}
+
+// spawnChoices returns the bases a newly spawned enemy may head for,
+// taking round-robin's turn
+func (g *Game) spawnChoices() []Point {
+	switch g.Routing {
+	case RouteWeakest:
+		return g.weakestBases()
+	case RouteRoundRobin:
+		i := g.nextBase % len(g.Bases)
+		g.nextBase = (i + 1) % len(g.Bases)
+		return g.Bases[i : i+1]
+	}
+	return g.Bases
+}
+
+// rerouteChoices returns the bases an enemy already on its way may head
+// for. Under round-robin it keeps the base it was given.
+func (g *Game) rerouteChoices(e *Enemy) []Point {
+	switch g.Routing {
+	case RouteWeakest:
+		return g.weakestBases()
+	case RouteRoundRobin:
+		if len(e.Path) > 0 {
+			return e.Path[len(e.Path)-1:]
+		}
+	}
+	return g.Bases
+}
+
+// routeTo returns the cheapest path from a cell to one of bases, or to any
+// base if none of those can be reached. nil if no base can be.
+func (g *Game) routeTo(from Point, bases []Point) []Point {
+	path := g.pathToAny(from, bases, nil)
+	if path == nil && len(bases) < len(g.Bases) {
+		path = g.pathToBase(from, nil)
+	}
+	return path
+}
+
+// rerouteToBases sends every enemy on the board toward the base the routing
+// policy picks for it now: walkers from where they stand, flyers straight
+// on from wherever they are
+func (g *Game) rerouteToBases() {
+	g.RecalculateEnemyPaths(nil)
+	for _, e := range g.Enemies {
+		if e.Flies() && e.PathIndex < len(e.Path) {
+			here := Point{X: int(e.X) / CellSize, Y: int(e.Y) / CellSize}
+			e.Path[len(e.Path)-1] = nearestBase(g.rerouteChoices(e), here)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+// twoBases has a spawn much closer to one base than the other
+var twoBases = []string{
+	"#############",
+	"#B.........B#",
+	"#...........#",
+	"#..S........#",
+	"#############",
+}
+
+var (
+	nearBase = Point{X: 1, Y: 1}
+	farBase  = Point{X: 11, Y: 1}
+)
+
+// goal returns the base an enemy's route ends at
+func goal(e *Enemy) Point {
+	return e.Path[len(e.Path)-1]
+}
+
+func TestRoutingPolicyPicksEachSpawnsBase(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		policy  RoutingPolicy
+		damaged int // Index of a base to damage first; -1 for none
+		want    []Point
+	}{
+		{name: "nearest", policy: RouteNearest, damaged: -1, want: []Point{nearBase, nearBase, nearBase}},
+		{name: "nearest ignores HP", policy: RouteNearest, damaged: 1, want: []Point{nearBase, nearBase, nearBase}},
+		{name: "weakest without HP is nearest", policy: RouteWeakest, damaged: -1, want: []Point{nearBase, nearBase, nearBase}},
+		{name: "weakest", policy: RouteWeakest, damaged: 1, want: []Point{farBase, farBase, farBase}},
+		{name: "round-robin", policy: RouteRoundRobin, damaged: -1, want: []Point{nearBase, farBase, nearBase}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestGame(t, twoBases...)
+			if g.Bases[0] != nearBase || g.Bases[1] != farBase {
+				t.Fatalf("bases = %v, want %v then %v", g.Bases, nearBase, farBase)
+			}
+			g.SetRouting(tc.policy)
+			if tc.damaged >= 0 {
+				g.DamageBase(tc.damaged, 10)
+			}
+			for i, want := range tc.want {
+				g.spawnEnemy(EnemyNormal)
+				e := g.Enemies[len(g.Enemies)-1]
+				checkRoute(t, g, e.Path, g.Spawns[0], want)
+				if got := goal(e); got != want {
+					t.Errorf("enemy %d heads for %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBaseDamageReroutesEnemiesUnderWeakest(t *testing.T) {
+	g := newTestGame(t, twoBases...)
+	g.SetRouting(RouteWeakest)
+	g.spawnEnemy(EnemyNormal)
+	g.spawnEnemy(EnemyFlying)
+	walker, flyer := g.Enemies[0], g.Enemies[1]
+	for _, e := range g.Enemies {
+		if goal(e) != nearBase {
+			t.Fatalf("%s heads for %v before any damage, want %v", EnemyTypes[e.Kind].Name, goal(e), nearBase)
+		}
+	}
+
+	g.DamageBase(1, 10)
+
+	if goal(walker) != farBase {
+		t.Errorf("walker heads for %v, want the damaged %v", goal(walker), farBase)
+	}
+	if goal(flyer) != farBase {
+		t.Errorf("flyer heads for %v, want the damaged %v", goal(flyer), farBase)
+	}
+
+	// The other base falling lower turns them back
+	g.DamageBase(0, 20)
+	if goal(walker) != nearBase || goal(flyer) != nearBase {
+		t.Errorf("enemies head for %v and %v, want the now weaker %v", goal(walker), goal(flyer), nearBase)
+	}
+}
+
+// leakAll steps the game until every enemy on the board has got through or
+// the game is over
+func leakAll(t *testing.T, g *Game) {
+	t.Helper()
+	for tick := 0; len(g.Enemies) > 0 && g.State == StatePlaying; tick++ {
+		if tick > 20*TPS {
+			t.Fatalf("%d enemies still walking after %d ticks", len(g.Enemies), tick)
+		}
+		g.Step(Input{})
+	}
+}
+
+func TestLeaksChargeTheBaseTheyReach(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy RoutingPolicy
+		want   Point
+	}{
+		{name: "nearest", policy: RouteNearest, want: nearBase},
+		{name: "weakest", policy: RouteWeakest, want: farBase},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestGame(t, twoBases...)
+
+			// Round-robin sends the second enemy to the far base; only it is
+			// left to get through
+			g.SetRouting(RouteRoundRobin)
+			g.spawnEnemy(EnemyNormal)
+			g.spawnEnemy(EnemyNormal)
+			g.Enemies[0].HP = 0
+			leakAll(t, g)
+
+			if g.State != StatePlaying {
+				t.Fatalf("state = %v after one leak, want still playing", g.State)
+			}
+			if want := []float64{BaseMaxHP, BaseMaxHP - LeakDamage}; !slices.Equal(g.BaseHP, want) {
+				t.Fatalf("base HP = %v, want %v", g.BaseHP, want)
+			}
+
+			g.SetRouting(tc.policy)
+			g.spawnEnemy(EnemyNormal)
+			if got := goal(g.Enemies[0]); got != tc.want {
+				t.Errorf("new enemy heads for %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLosingABaseLosesTheGame(t *testing.T) {
+	g := newTestGame(t, twoBases...)
+	for leak := 1; leak <= int(BaseMaxHP/LeakDamage); leak++ {
+		if g.State != StatePlaying {
+			t.Fatalf("state = %v after %d leaks, want still playing", g.State, leak-1)
+		}
+		g.spawnEnemy(EnemyNormal)
+		leakAll(t, g)
+	}
+	if g.State != StateLost {
+		t.Errorf("state = %v once the near base has no HP, want lost", g.State)
+	}
+	if g.BaseHP[1] != BaseMaxHP {
+		t.Errorf("far base HP = %v, want untouched %v", g.BaseHP[1], BaseMaxHP)
+	}
+}
@@ -0,0 +1,343 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const SaveVersion = 5 // Bumped whenever the save format changes
+
+// SaveFile is a game in progress, as written to disk. Pointers between
+// entities (a tower's target, a trap's miner, a shell's target and tower)
+// are stored as indices.
+type SaveFile struct {
+	Version int
+
+	Grid        [][]TileType
+	Spawns      []Point
+	Bases       []Point
+	Paths       [][]Point
+	NextLane    int
+	Routing     RoutingPolicy
+	BaseHP      []float64
+	NextBase    int
+	Towers      []savedTower
+	Enemies     []savedEnemy
+	Traps       []savedTrap
+	Projectiles []savedProjectile
+	Lasers      []*Laser
+	Pickups     []Pickup
+	KillZone    *KillZone
+
+	State        GameState
+	Resources    int
+	Tick         int
+	Seed         uint64
+	Endless      bool
+	LongestPath  int
+	SpawnStall   int
+	ZoneCooldown int
+	Overkill     float64
+
+	// Wave counters
+	CurrentWave     int
+	ClearedWave     int
+	WaveNearLeak    bool
+	EnemiesThisWave int
+	WaveSpawned     int
+	WaveDelay       int
+	SpawnTimer      int
+	WaveKills       int
+	TotalKills      int
+	TotalSpawned    int
+	MegaWave        bool
+	SurgeKills      int
+	SurgeProgress   float64
+	WaveSurges      int
+	WaveDamage      map[TowerKind]float64
+	LastWaveDamage  map[TowerKind]float64
+	OverkillCarry   float64
+
+	// Rules the game was started or switched to
+	SiegeEnabled       bool
+	SmoothPaths        bool
+	SurgeEnabled       bool
+	RageEnabled        bool
+	FormationsEnabled  bool
+	OverkillEnabled    bool
+	PickupsEnabled     bool
+	RealisticEconomy   bool
+	BuildQueueEnabled  bool
+	BuildQueue         []QueuedBuild
+	MaxTowers          int
+	RequireLineOfSight bool
+	Separation         bool
+	SkipDoomed         bool
+
+	// Mode and map source, so a loaded game restarts as the same kind of game
+	Daily        bool
+	DailyDate    time.Time
+	DailyBest    int
+	PracticeWave int
+	Generated    bool
+	MapSeed      int64
+	Tutorial     bool
+	MapName      string
+	TwoLanes     bool
+}
+
+// savedTower is a tower plus the unexported state it needs to carry on
+type savedTower struct {
+	Tower
+	Target     int // Index into Enemies; -1 for none
+	BeamCharge float64
+	ManualFire bool
+}
+
+// savedEnemy is an enemy plus the unexported state it needs to carry on
+type savedEnemy struct {
+	Enemy
+	SinceHit     int
+	ShredTimer   int
+	BounceImmune int
+	SurfaceTimer int
+	StunImmune   int
+}
+
+// savedTrap is a trap with its miner as an index into Towers
+type savedTrap struct {
+	Trap
+	Owner int
+}
+
+// savedProjectile is a shell with its target as an index into Enemies (-1
+// for none) and its tower as an index into Towers
+type savedProjectile struct {
+	Projectile
+	Target int
+	Tower  int
+}
+
+// Save serializes the game in progress
+func (g *Game) Save() ([]byte, error) {
+	f := SaveFile{
+		Version: SaveVersion,
+		Grid:    g.Grid, Spawns: g.Spawns, Bases: g.Bases, Paths: g.Paths, NextLane: g.NextLane,
+		Routing: g.Routing, BaseHP: g.BaseHP, NextBase: g.nextBase,
+		Lasers: g.Lasers, Pickups: g.Pickups, KillZone: g.KillZone,
+		State: g.State, Resources: g.Resources, Tick: g.Tick, Seed: g.Seed, Endless: g.Endless,
+		LongestPath: g.LongestPath, SpawnStall: g.SpawnStall,
+		ZoneCooldown: g.ZoneCooldown, Overkill: g.TotalOverkill,
+		CurrentWave: g.CurrentWave, ClearedWave: g.clearedWave, WaveNearLeak: g.waveNearLeak,
+		EnemiesThisWave: g.EnemiesThisWave, WaveSpawned: g.WaveSpawned, WaveDelay: g.WaveDelay,
+		SpawnTimer: g.spawnTimer, WaveKills: g.WaveKills, TotalKills: g.TotalKills,
+		TotalSpawned: g.totalSpawned, MegaWave: g.MegaWave,
+		SurgeKills: g.surgeKills, SurgeProgress: g.surgeProgress, WaveSurges: g.WaveSurges,
+		WaveDamage: g.waveDamage, LastWaveDamage: g.LastWaveDamage, OverkillCarry: g.overkillCarry,
+		SiegeEnabled: g.SiegeEnabled, SmoothPaths: g.SmoothPaths, SurgeEnabled: g.SurgeEnabled,
+		RageEnabled: g.RageEnabled, FormationsEnabled: g.FormationsEnabled, OverkillEnabled: g.OverkillEnabled,
+		PickupsEnabled: g.PickupsEnabled, RealisticEconomy: g.RealisticEconomy,
+		BuildQueueEnabled: g.BuildQueueEnabled, BuildQueue: g.BuildQueue, MaxTowers: g.MaxTowers,
+		RequireLineOfSight: g.RequireLineOfSight, Separation: g.Separation, SkipDoomed: g.SkipDoomed,
+		Daily: g.Daily, DailyDate: g.DailyDate, DailyBest: g.DailyBest, PracticeWave: g.PracticeWave,
+		Generated: g.Generated, MapSeed: g.MapSeed, Tutorial: g.Tutorial, MapName: g.MapName, TwoLanes: g.TwoLanes,
+	}
+	for _, t := range g.Towers {
+		f.Towers = append(f.Towers, savedTower{Tower: *t, Target: slices.Index(g.Enemies, t.Target), BeamCharge: t.BeamCharge, ManualFire: t.ManualFire})
+	}
+	for _, e := range g.Enemies {
+		f.Enemies = append(f.Enemies, savedEnemy{Enemy: *e, SinceHit: e.sinceHit, ShredTimer: e.shredTimer,
+			BounceImmune: e.bounceImmune, SurfaceTimer: e.surfaceTimer, StunImmune: e.stunImmune})
+	}
+	for _, tr := range g.Traps {
+		f.Traps = append(f.Traps, savedTrap{Trap: *tr, Owner: slices.Index(g.Towers, tr.Owner)})
+	}
+	for _, p := range g.Projectiles {
+		f.Projectiles = append(f.Projectiles, savedProjectile{Projectile: *p,
+			Target: slices.Index(g.Enemies, p.target), Tower: slices.Index(g.Towers, p.Tower)})
+	}
+	return json.Marshal(f)
+}
+
+// LoadGame restores a game saved by Save. Saves from another version of the
+// format are refused rather than half-read.
+func LoadGame(data []byte) (*Game, error) {
+	var f SaveFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Version != SaveVersion {
+		return nil, fmt.Errorf("save format %d, this game reads %d", f.Version, SaveVersion)
+	}
+
+	if !rectangular(f.Grid) {
+		return nil, fmt.Errorf("save's grid isn't a rectangle at least %d cells each way", MinGridSize)
+	}
+	g := NewGame(len(f.Grid[0]), len(f.Grid))
+	if len(f.Spawns) == 0 || len(f.Bases) == 0 || len(f.Paths) != len(f.Spawns) {
+		return nil, fmt.Errorf("save has %d spawns, %d bases, and %d paths; want at least one spawn and base, and a path per spawn",
+			len(f.Spawns), len(f.Bases), len(f.Paths))
+	}
+	if f.BaseHP != nil && len(f.BaseHP) != len(f.Bases) {
+		return nil, fmt.Errorf("save has %d base HPs for %d bases", len(f.BaseHP), len(f.Bases))
+	}
+	if f.Routing < 0 || f.Routing >= numRoutingPolicies {
+		return nil, fmt.Errorf("save has unknown routing policy %d", f.Routing)
+	}
+	g.Grid, g.Spawns, g.Bases, g.Paths, g.NextLane = f.Grid, f.Spawns, f.Bases, f.Paths, f.NextLane
+	g.Routing, g.BaseHP, g.nextBase = f.Routing, f.BaseHP, f.NextBase
+	g.PathBlocked = slices.ContainsFunc(g.Paths, func(p []Point) bool { return p == nil })
+	g.Lasers, g.Pickups, g.KillZone = f.Lasers, f.Pickups, f.KillZone
+	g.State, g.Resources, g.Tick, g.Seed, g.Endless = f.State, f.Resources, f.Tick, f.Seed, f.Endless
+	g.LongestPath, g.SpawnStall = f.LongestPath, f.SpawnStall
+	g.ZoneCooldown, g.TotalOverkill = f.ZoneCooldown, f.Overkill
+	g.CurrentWave, g.clearedWave, g.waveNearLeak = f.CurrentWave, f.ClearedWave, f.WaveNearLeak
+	g.EnemiesThisWave, g.WaveSpawned, g.WaveDelay = f.EnemiesThisWave, f.WaveSpawned, f.WaveDelay
+	g.spawnTimer, g.WaveKills, g.TotalKills = f.SpawnTimer, f.WaveKills, f.TotalKills
+	g.totalSpawned, g.MegaWave = f.TotalSpawned, f.MegaWave
+	g.surgeKills, g.surgeProgress, g.WaveSurges = f.SurgeKills, f.SurgeProgress, f.WaveSurges
+	g.waveDamage, g.LastWaveDamage, g.overkillCarry = f.WaveDamage, f.LastWaveDamage, f.OverkillCarry
+	g.SiegeEnabled, g.SmoothPaths, g.SurgeEnabled = f.SiegeEnabled, f.SmoothPaths, f.SurgeEnabled
+	g.RageEnabled, g.FormationsEnabled, g.OverkillEnabled = f.RageEnabled, f.FormationsEnabled, f.OverkillEnabled
+	g.PickupsEnabled, g.RealisticEconomy = f.PickupsEnabled, f.RealisticEconomy
+	g.BuildQueueEnabled, g.BuildQueue, g.MaxTowers = f.BuildQueueEnabled, f.BuildQueue, f.MaxTowers
+	g.RequireLineOfSight, g.Separation, g.SkipDoomed = f.RequireLineOfSight, f.Separation, f.SkipDoomed
+	g.Daily, g.DailyDate, g.DailyBest, g.PracticeWave = f.Daily, f.DailyDate, f.DailyBest, f.PracticeWave
+	g.Generated, g.MapSeed, g.Tutorial, g.MapName, g.TwoLanes = f.Generated, f.MapSeed, f.Tutorial, f.MapName, f.TwoLanes
+
+	g.Enemies = nil
+	for _, se := range f.Enemies {
+		e := se.Enemy
+		e.sinceHit, e.shredTimer, e.bounceImmune = se.SinceHit, se.ShredTimer, se.BounceImmune
+		e.surfaceTimer, e.stunImmune = se.SurfaceTimer, se.StunImmune
+		g.Enemies = append(g.Enemies, &e)
+	}
+	g.Towers = nil
+	for _, st := range f.Towers {
+		t := st.Tower
+		t.BeamCharge, t.ManualFire = st.BeamCharge, st.ManualFire
+		if st.Target >= 0 && st.Target < len(g.Enemies) {
+			t.Target = g.Enemies[st.Target]
+		}
+		g.Towers = append(g.Towers, &t)
+	}
+	g.Traps = nil
+	for _, st := range f.Traps {
+		tr := st.Trap
+		if st.Owner >= 0 && st.Owner < len(g.Towers) {
+			tr.Owner = g.Towers[st.Owner]
+		}
+		g.Traps = append(g.Traps, &tr)
+	}
+	g.Projectiles = nil
+	for _, sp := range f.Projectiles {
+		if sp.Tower < 0 || sp.Tower >= len(g.Towers) {
+			continue // Its tower was sold; without it the shell can't land
+		}
+		p := sp.Projectile
+		p.Tower = g.Towers[sp.Tower]
+		if sp.Target >= 0 && sp.Target < len(g.Enemies) {
+			p.aimAt(g.Enemies[sp.Target]) // Rebuilds the target's incoming damage
+		}
+		g.Projectiles = append(g.Projectiles, &p)
+	}
+	g.recalculateSlow()
+	g.CoverageDirty = true
+	return g, nil
+}
+
+// savePath returns the file the game in progress is saved to
+func savePath() (string, error) {
+	return ConfigPath("save.json")
+}
+
+// sessionPath returns the file the game in progress is kept in when the
+// window closes, for the next launch to continue
+func sessionPath() (string, error) {
+	return ConfigPath("last_session.json")
+}
+
+// SaveToDisk writes the game in progress to the save file
+func (g *Game) SaveToDisk() error {
+	path, err := savePath()
+	if err != nil {
+		return err
+	}
+	return g.saveFile(path)
+}
+
+// LoadFromDisk reads the game in the save file
+func LoadFromDisk() (*Game, error) {
+	path, err := savePath()
+	if err != nil {
+		return nil, err
+	}
+	return loadFile(path)
+}
+
+// SaveSession writes the game in progress to the last session file
+func (g *Game) SaveSession() error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	return g.saveFile(path)
+}
+
+// LoadSession reads the game in the last session file
+func LoadSession() (*Game, error) {
+	path, err := sessionPath()
+	if err != nil {
+		return nil, err
+	}
+	return loadFile(path)
+}
+
+// HasSession returns true if there's a last session to continue
+func HasSession() bool {
+	path, err := sessionPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// ClearSession removes the last session file, if there is one
+func ClearSession() error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// saveFile writes the game in progress to a file
+func (g *Game) saveFile(path string) error {
+	data, err := g.Save()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// loadFile reads a game saved by saveFile
+func loadFile(path string) (*Game, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadGame(data)
+}
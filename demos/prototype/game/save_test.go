@@ -0,0 +1,144 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useTempConfig points the config directory at a fresh temp dir
+func useTempConfig(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir) // Linux, BSD
+	t.Setenv("HOME", dir)            // macOS
+	t.Setenv("AppData", dir)         // Windows
+}
+
+func TestSessionRoundTrips(t *testing.T) {
+	useTempConfig(t)
+	g := newTestGame(t, openField...)
+	buildTower(t, g, 5, 1, KindBasic)
+	g.spawnEnemy(EnemyNormal)
+	for range 30 {
+		g.step()
+	}
+
+	if HasSession() {
+		t.Fatal("session exists before saving")
+	}
+	if err := g.SaveSession(); err != nil {
+		t.Fatal(err)
+	}
+	if !HasSession() {
+		t.Fatal("no session after saving")
+	}
+	loaded, err := LoadSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := loaded.StateDigest(), g.StateDigest(); got != want {
+		t.Errorf("loaded digest = %x, want %x", got, want)
+	}
+	if err := ClearSession(); err != nil {
+		t.Fatal(err)
+	}
+	if HasSession() {
+		t.Error("session still exists after clearing")
+	}
+	if err := ClearSession(); err != nil {
+		t.Errorf("clearing a missing session: %v", err)
+	}
+}
+
+func TestLoadKeepsRulesModeAndCounters(t *testing.T) {
+	g := NewGeneratedGame(5)
+	g.SiegeEnabled, g.SmoothPaths, g.SurgeEnabled, g.RageEnabled = true, true, true, true
+	g.FormationsEnabled, g.OverkillEnabled, g.PickupsEnabled = true, true, true
+	g.RealisticEconomy, g.BuildQueueEnabled, g.MaxTowers = true, true, 12
+	g.BuildQueue = []QueuedBuild{{Cell: Point{X: 3, Y: 4}, Kind: KindSniper}}
+	g.RequireLineOfSight, g.Separation, g.SkipDoomed = true, true, true
+	g.Daily, g.DailyDate, g.DailyBest = true, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), 1234
+	g.PracticeWave, g.Tutorial, g.MapName, g.TwoLanes = 3, true, "arena", true
+	g.surgeKills, g.surgeProgress, g.WaveSurges = 4, 2.5, 1
+	g.waveDamage = map[TowerKind]float64{KindBasic: 40, KindCannon: 90}
+	g.LastWaveDamage = map[TowerKind]float64{KindRapid: 15}
+	g.waveNearLeak, g.overkillCarry = true, 0.75
+
+	data, err := g.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadGame(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []struct {
+		name string
+		get  func(*Game) any
+	}{
+		{"rules", func(g *Game) any {
+			return []any{g.SiegeEnabled, g.SmoothPaths, g.SurgeEnabled, g.RageEnabled, g.FormationsEnabled,
+				g.OverkillEnabled, g.PickupsEnabled, g.RealisticEconomy, g.BuildQueueEnabled, g.BuildQueue,
+				g.MaxTowers, g.RequireLineOfSight, g.Separation, g.SkipDoomed}
+		}},
+		{"mode", func(g *Game) any {
+			return []any{g.Daily, g.DailyDate.Unix(), g.DailyBest, g.PracticeWave}
+		}},
+		{"map source", func(g *Game) any {
+			return []any{g.Generated, g.MapSeed, g.Tutorial, g.MapName, g.TwoLanes}
+		}},
+		{"counters", func(g *Game) any {
+			return []any{g.surgeKills, g.surgeProgress, g.WaveSurges, g.waveDamage, g.LastWaveDamage,
+				g.waveNearLeak, g.overkillCarry}
+		}},
+	} {
+		if got, want := field.get(loaded), field.get(g); !reflect.DeepEqual(got, want) {
+			t.Errorf("loaded %s = %v, want %v", field.name, got, want)
+		}
+	}
+}
+
+func TestLoadedGamePlaysOnIdentically(t *testing.T) {
+	g := NewGameSeed(9)
+	g.Resources = 10000
+	g.SmoothPaths, g.Separation, g.SkipDoomed, g.RageEnabled = true, true, true, true
+	for tick := range 30 * TPS { // Towers up, the first wave under way
+		g.Step(script[tick])
+	}
+	if g.State != StatePlaying || len(g.Enemies) == 0 {
+		t.Fatalf("game is in state %v with %d enemies, want one under way", g.State, len(g.Enemies))
+	}
+	data, err := g.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadGame(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for tick := range 10 * TPS {
+		if loaded.StateDigest() != g.StateDigest() {
+			t.Fatalf("loaded game diverged %d ticks after loading", tick)
+		}
+		g.Step(Input{})
+		loaded.Step(Input{})
+	}
+}
+
+func TestLoadRefusesOtherVersions(t *testing.T) {
+	data, err := NewGame(GridWidth, GridHeight).Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := strings.Replace(string(data), fmt.Sprintf(`"Version":%d`, SaveVersion), `"Version":1`, 1)
+	if old == string(data) {
+		t.Fatal("save has no version")
+	}
+	if _, err := LoadGame([]byte(old)); err == nil {
+		t.Error("loaded a save from another version")
+	}
+}
@@ -0,0 +1,71 @@
+package game
+
+import "math"
+
+const (
+	SeparationRadius = EnemyRadius * 2 // Enemies closer than this push apart
+	SeparationPush   = 0.5             // Most pixels a pair pushes each other per tick
+	SeparationMax    = CellSize / 4    // Furthest an enemy is pushed off the line it's walking
+)
+
+// separateEnemies nudges enemies sharing a cell apart, sideways to the way
+// they're walking, so a crowd on one path spreads out a little instead of
+// stacking into a single dot. Pushes never move an enemy along its path, off
+// walkable tiles, or more than SeparationMax off its line. Pairs are visited
+// in enemy order, so the result is deterministic.
+func (g *Game) separateEnemies() {
+	cells := make(map[Point][]*Enemy)
+	var order []Point // Cells in the order their first enemy was seen
+	for _, e := range g.Enemies {
+		if e.Burrowed > 0 || e.PathIndex >= len(e.Path) {
+			continue
+		}
+		c := Point{X: int(e.X) / CellSize, Y: int(e.Y) / CellSize}
+		if len(cells[c]) == 0 {
+			order = append(order, c)
+		}
+		cells[c] = append(cells[c], e)
+	}
+	for _, c := range order {
+		crowd := cells[c]
+		for i, a := range crowd {
+			for _, b := range crowd[i+1:] {
+				d := math.Hypot(a.X-b.X, a.Y-b.Y)
+				if d >= SeparationRadius {
+					continue
+				}
+				push := min(SeparationPush, (SeparationRadius-d)/2)
+				g.nudgeSideways(a, b, push, 1)
+				g.nudgeSideways(b, a, push, -1)
+			}
+		}
+	}
+}
+
+// nudgeSideways pushes e across its direction of travel, away from other.
+// If they're level, tie (±1) picks the side, so exact overlaps still split.
+func (g *Game) nudgeSideways(e, other *Enemy, push, tie float64) {
+	target := e.Path[e.PathIndex]
+	tx, ty := CellCenter(target.X, target.Y)
+	hx, hy := Unit(tx-e.X, ty-e.Y)
+	if hx == 0 && hy == 0 {
+		return // Standing on its waypoint
+	}
+	px, py := -hy, hx
+	side := (e.X-other.X)*px + (e.Y-other.Y)*py
+	sign := tie
+	if side > 0 {
+		sign = 1
+	} else if side < 0 {
+		sign = -1
+	}
+
+	// Keep within SeparationMax of the line through the waypoint
+	offset := (e.X-tx)*px + (e.Y-ty)*py
+	moved := math.Max(-SeparationMax, math.Min(SeparationMax, offset+sign*push)) - offset
+	nx, ny := e.X+px*moved, e.Y+py*moved
+	if !g.isWalkable(int(nx)/CellSize, int(ny)/CellSize) {
+		return
+	}
+	e.X, e.Y = nx, ny
+}
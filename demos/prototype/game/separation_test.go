@@ -0,0 +1,57 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+// winding is a corridor with several turns for a crowd to push through
+var winding = []string{
+	"###########",
+	"#S........#",
+	"#########.#",
+	"#.........#",
+	"#.#########",
+	"#........B#",
+	"###########",
+}
+
+// walkCrowd spawns a crowd on one spot and walks it until every enemy has
+// arrived, failing the test if one leaves walkable ground or gets stuck.
+// Returns where every enemy stood on every tick.
+func walkCrowd(t *testing.T, separation bool) [][2]float64 {
+	t.Helper()
+	const crowd = 20
+	g := newTestGame(t, winding...)
+	g.Separation = separation
+	for range crowd {
+		g.spawnEnemy(EnemyNormal)
+	}
+	var trace [][2]float64
+	for tick := 0; len(g.Enemies) > 0; tick++ {
+		if tick > 20*TPS {
+			t.Fatalf("%d enemies still walking after %d ticks", len(g.Enemies), tick)
+		}
+		g.updateEnemies()
+		for _, e := range g.Enemies {
+			if !g.isWalkable(int(e.X)/CellSize, int(e.Y)/CellSize) {
+				t.Fatalf("enemy pushed onto unwalkable ground at (%v, %v)", e.X, e.Y)
+			}
+			trace = append(trace, [2]float64{e.X, e.Y})
+		}
+	}
+	if g.State != StateLost || g.TotalKills != 0 {
+		t.Fatalf("crowd didn't all reach the base")
+	}
+	return trace
+}
+
+func TestSeparatedCrowdsStillReachTheBase(t *testing.T) {
+	trace := walkCrowd(t, true)
+	if !slices.Equal(walkCrowd(t, true), trace) {
+		t.Error("the same crowd walked differently the second time")
+	}
+	if slices.Equal(walkCrowd(t, false), trace) {
+		t.Error("separation didn't move anyone")
+	}
+}
@@ -0,0 +1,28 @@
+package game
+
+const (
+	TowerMaxHP        = 150.0 // Tower health, only used in siege mode
+	EnemyAttackDamage = 0.5   // Damage per tick an enemy deals to a tower in its way
+)
+
+// BlockingTower returns the tower standing on an enemy's next waypoint, if
+// siege mode is on and one is there
+func (g *Game) BlockingTower(e *Enemy) *Tower {
+	if !g.SiegeEnabled || e.PathIndex >= len(e.Path) {
+		return nil
+	}
+	next := e.Path[e.PathIndex]
+	return g.TowerAt(next.X, next.Y)
+}
+
+// attackTower has an enemy hit the tower blocking its way, destroying it at 0 HP
+func (g *Game) attackTower(t *Tower) {
+	t.HP -= EnemyAttackDamage * (1 + g.Rage())
+	if t.HP > 0 {
+		return
+	}
+	g.deleteTower(t)
+	cx, cy := CellCenter(t.X, t.Y)
+	g.AddFloatingText(cx, cy, "DESTROYED")
+	g.OnGridChanged(nil, true)
+}
@@ -0,0 +1,132 @@
+package game
+
+import "math"
+
+// CellCenter returns the pixel center of a grid cell
+func CellCenter(x, y int) (float64, float64) {
+	return float64(x*CellSize) + CellSize/2, float64(y*CellSize) + CellSize/2
+}
+
+// blocksSight returns true if a tile stops line of sight
+func (g *Game) blocksSight(x, y int) bool {
+	if !g.InGrid(x, y) {
+		return true
+	}
+	return g.Grid[y][x] == TileWall
+}
+
+// hasLineOfSight reports whether no wall lies between two pixel positions
+func (g *Game) hasLineOfSight(fromX, fromY, toX, toY float64) bool {
+	return traverse(fromX, fromY, toX, toY, g.blocksSight)
+}
+
+// traverse walks the grid cells crossed by the segment between two pixel
+// positions (Amanatides-Woo traversal), after the one it starts in, and
+// reports whether none of them is blocked
+func traverse(fromX, fromY, toX, toY float64, blocked func(x, y int) bool) bool {
+	x, y := int(fromX)/CellSize, int(fromY)/CellSize
+	endX, endY := int(toX)/CellSize, int(toY)/CellSize
+	dx, dy := toX-fromX, toY-fromY
+
+	stepX, tMaxX, tDeltaX := traversalAxis(fromX, dx, x)
+	stepY, tMaxY, tDeltaY := traversalAxis(fromY, dy, y)
+
+	for x != endX || y != endY {
+		if tMaxX < tMaxY {
+			x += stepX
+			tMaxX += tDeltaX
+		} else {
+			y += stepY
+			tMaxY += tDeltaY
+		}
+		if blocked(x, y) {
+			return false
+		}
+		// Guard against float drift stepping past the end cell
+		if tMaxX > 1 && tMaxY > 1 {
+			break
+		}
+	}
+	return true
+}
+
+// traversalAxis returns the cell step direction, the segment parameter t at the
+// first cell boundary crossed, and the t needed to cross one whole cell
+func traversalAxis(from, d float64, cell int) (step int, tMax, tDelta float64) {
+	switch {
+	case d > 0:
+		boundary := float64((cell + 1) * CellSize)
+		return 1, (boundary - from) / d, CellSize / d
+	case d < 0:
+		boundary := float64(cell * CellSize)
+		return -1, (boundary - from) / d, CellSize / -d
+	default:
+		return 0, math.Inf(1), math.Inf(1)
+	}
+}
+
+// canSee returns true if a tower at the given cell can see a pixel position.
+// Always true unless line-of-sight mode is enabled.
+func (g *Game) canSee(towerX, towerY int, px, py float64) bool {
+	if !g.RequireLineOfSight {
+		return true
+	}
+	cx, cy := CellCenter(towerX, towerY)
+	return g.hasLineOfSight(cx, cy, px, py)
+}
+
+// VisibleCells returns the cells a tower at (x, y) could target: within range
+// and, in line-of-sight mode, not hidden behind walls
+func (g *Game) VisibleCells(x, y int, rng float64) []Point {
+	var cells []Point
+	for gy := range g.Grid {
+		for gx := range g.Grid[gy] {
+			c := Point{X: gx, Y: gy}
+			if g.covers(x, y, rng, c) {
+				cells = append(cells, c)
+			}
+		}
+	}
+	return cells
+}
+
+// covers returns true if a tower at (x, y) with the given range can reach the center of cell c
+func (g *Game) covers(x, y int, rng float64, c Point) bool {
+	tx, ty := CellCenter(x, y)
+	px, py := CellCenter(c.X, c.Y)
+	return math.Hypot(px-tx, py-ty) <= rng && g.canSee(x, y, px, py)
+}
+
+// GapsCovered returns how many path cells no tower covers now that a tower
+// of a kind at (x, y) would
+func (g *Game) GapsCovered(x, y int, kind TowerKind) int {
+	score := 0
+	rng := TowerTypes[kind].Range
+	for _, p := range g.GapCells {
+		if g.covers(x, y, rng, p) {
+			score++
+		}
+	}
+	return score
+}
+
+// UncoveredPathCells returns the path cells outside every tower's range
+func (g *Game) UncoveredPathCells() []Point {
+	var gaps []Point
+	for _, p := range g.pathCells() {
+		covered := false
+		for _, t := range g.Towers {
+			if TowerTypes[t.Kind].Damage == 0 {
+				continue // Tar pits slow but never hurt
+			}
+			if g.covers(t.X, t.Y, g.EffectiveStats(t).Range, p) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			gaps = append(gaps, p)
+		}
+	}
+	return gaps
+}
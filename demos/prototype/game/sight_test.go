@@ -0,0 +1,52 @@
+package game
+
+import "testing"
+
+// placeEnemy spawns an enemy and moves it to the center of a cell
+func placeEnemy(g *Game, x, y int) *Enemy {
+	g.spawnEnemy(EnemyNormal)
+	e := g.Enemies[len(g.Enemies)-1]
+	e.X, e.Y = CellCenter(x, y)
+	return e
+}
+
+func TestLineOfSightBlocksShotsThroughWalls(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		required bool
+		wantHit  bool
+	}{
+		{name: "required", required: true, wantHit: false},
+		{name: "not required", required: false, wantHit: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestGame(t, openField...)
+			g.RequireLineOfSight = tc.required
+			g.Grid[2][3] = TileWall // Between the tower and the enemy below it
+			buildTower(t, g, 3, 1, KindBasic)
+			e := placeEnemy(g, 3, 3)
+			hp := e.HP
+
+			g.updateTowers()
+
+			if hit := e.HP < hp; hit != tc.wantHit {
+				t.Errorf("hit = %v, want %v", hit, tc.wantHit)
+			}
+		})
+	}
+}
+
+func TestLineOfSightAllowsShotsPastWalls(t *testing.T) {
+	g := newTestGame(t, openField...)
+	g.RequireLineOfSight = true
+	g.Grid[2][3] = TileWall // Beside the line of fire, not on it
+	buildTower(t, g, 4, 1, KindBasic)
+	e := placeEnemy(g, 4, 3)
+	hp := e.HP
+
+	g.updateTowers()
+
+	if e.HP >= hp {
+		t.Errorf("tower with a clear line didn't hit")
+	}
+}
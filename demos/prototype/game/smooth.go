@@ -0,0 +1,94 @@
+package game
+
+import "math"
+
+// A* on a grid gives staircase routes across open ground. With smoothing on,
+// enemy routes drop every waypoint a straight walk can skip, so enemies cut
+// across open areas. The shared path (drawing, coverage, maze length) stays
+// cell by cell; only the routes enemies follow are smoothed.
+
+// smoothing returns true if enemy routes are smoothed. Siege enemies attack
+// the tower on their next waypoint, so they keep cell-by-cell routes.
+func (g *Game) smoothing() bool {
+	return g.SmoothPaths && !g.SiegeEnabled
+}
+
+// smoothPath removes the waypoints of a path that an enemy can walk straight
+// past, reusing its storage. Returns the path unchanged if smoothing is off.
+func (g *Game) smoothPath(path []Point) []Point {
+	if !g.smoothing() || len(path) < 3 {
+		return path
+	}
+	out := path[:1]
+	from := path[0]
+	for i := 1; i < len(path); {
+		// Reach as far ahead as a straight walk allows (always the next cell)
+		j := i
+		for j+1 < len(path) && g.canWalkStraight(from, path[j+1]) {
+			j++
+		}
+		from = path[j]
+		out = append(out, from) // Never past j, so unread points are intact
+		i = j + 1
+	}
+	return out
+}
+
+// canWalkStraight reports whether an enemy can walk directly between two cell
+// centers: its whole body width stays on walkable cells, and it only meets a
+// bounce pad at the end, where it'll be on a waypoint the pad can catch it on
+func (g *Game) canWalkStraight(a, b Point) bool {
+	ax, ay := CellCenter(a.X, a.Y)
+	bx, by := CellCenter(b.X, b.Y)
+	blocked := func(x, y int) bool {
+		return !g.isWalkable(x, y) || (Point{X: x, Y: y} != b && g.inBounceZone(Point{X: x, Y: y}))
+	}
+	// Trace the center line and both edges of the enemy's body
+	nx, ny := Unit(by-ay, ax-bx)
+	for _, side := range []float64{0, -EnemyRadius, EnemyRadius} {
+		ox, oy := nx*side, ny*side
+		if !traverse(ax+ox, ay+oy, bx+ox, by+oy, blocked) {
+			return false
+		}
+	}
+	return true
+}
+
+// inBounceZone returns true if a bounce pad (ready or not) reaches a cell
+func (g *Game) inBounceZone(c Point) bool {
+	px, py := CellCenter(c.X, c.Y)
+	for _, t := range g.Towers {
+		if !TowerTypes[t.Kind].Bounce {
+			continue
+		}
+		tx, ty := CellCenter(t.X, t.Y)
+		if math.Hypot(px-tx, py-ty) <= g.EffectiveStats(t).Range {
+			return true
+		}
+	}
+	return false
+}
+
+// stepsLeft returns how many cell steps an enemy has left to the base,
+// counting each smoothed straight stretch as the steps it replaced. A flyer
+// counts them from the cell it's over, since its one stretch is the whole way.
+func (e *Enemy) stepsLeft() int {
+	if e.Flies() && e.PathIndex < len(e.Path) {
+		return heuristic(Point{X: int(e.X) / CellSize, Y: int(e.Y) / CellSize}, e.Path[len(e.Path)-1])
+	}
+	n := 0
+	for i := max(e.PathIndex, 1); i < len(e.Path); i++ {
+		a, b := e.Path[i-1], e.Path[i]
+		n += heuristic(a, b) // Manhattan distance: 1 per unsmoothed step
+	}
+	return n
+}
+
+// Unit scales a vector to length 1 (leaving a zero vector alone)
+func Unit(x, y float64) (float64, float64) {
+	l := math.Hypot(x, y)
+	if l == 0 {
+		return 0, 0
+	}
+	return x / l, y / l
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+// checkBodyClear fails the test if an enemy's body walking straight between
+// two cell centers ever overlaps an unwalkable cell, sampling every pixel of
+// its center line and both edges
+func checkBodyClear(t *testing.T, g *Game, a, b Point) {
+	t.Helper()
+	ax, ay := CellCenter(a.X, a.Y)
+	bx, by := CellCenter(b.X, b.Y)
+	nx, ny := Unit(by-ay, ax-bx)
+	steps := int(math.Ceil(math.Hypot(bx-ax, by-ay)))
+	for _, side := range []float64{0, -EnemyRadius, EnemyRadius} {
+		for i := 0; i <= steps; i++ {
+			f := float64(i) / float64(steps)
+			x := ax + (bx-ax)*f + nx*side
+			y := ay + (by-ay)*f + ny*side
+			cx, cy := int(math.Floor(x/CellSize)), int(math.Floor(y/CellSize))
+			if !g.isWalkable(cx, cy) {
+				t.Fatalf("walking %v to %v crosses unwalkable (%d, %d)", a, b, cx, cy)
+			}
+		}
+	}
+}
+
+func TestSmoothedRoutesNeverCrossWalls(t *testing.T) {
+	smoothed := 0
+	for seed := range int64(30) {
+		g := NewGeneratedGame(seed)
+		g.SmoothPaths = true
+		g.spawnEnemy(EnemyNormal)
+		e := g.Enemies[0]
+		if e.Path[0] != g.Spawns[0] || e.Path[len(e.Path)-1] != g.Bases[0] {
+			t.Fatalf("seed %d: smoothed route runs %v to %v", seed, e.Path[0], e.Path[len(e.Path)-1])
+		}
+		for i := 1; i < len(e.Path); i++ {
+			checkBodyClear(t, g, e.Path[i-1], e.Path[i])
+		}
+		if len(e.Path) < len(g.Paths[0]) {
+			smoothed++
+		}
+	}
+	if smoothed == 0 {
+		t.Error("no route was smoothed")
+	}
+}
+
+func TestUnsmoothedStepsLeftCountsWaypoints(t *testing.T) {
+	g := newTestGame(t, openField...)
+	g.spawnEnemy(EnemyNormal)
+	e := g.Enemies[0]
+	for e.PathIndex = 1; e.PathIndex < len(e.Path); e.PathIndex++ {
+		if got, want := e.stepsLeft(), len(e.Path)-e.PathIndex; got != want {
+			t.Errorf("stepsLeft at waypoint %d = %d, want %d", e.PathIndex, got, want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package game
+
+import (
+	"image/color"
+	"math"
+)
+
+const ExplosionDuration = 15 // Ticks an explosion ring takes to expand and fade
+
+// Explosion is the visual of a splash hit: a ring that grows to the splash
+// radius and fades
+type Explosion struct {
+	X, Y   float64 // Center in pixels
+	Radius float64 // Radius once fully grown
+	TTL    int     // Ticks remaining to display
+	Color  color.RGBA
+}
+
+// splash damages every other enemy within the tower's splash radius of
+// where its shot hit the target. The target is skipped by identity, not
+// position, so it's never hit twice and enemies right on top of it still are.
+func (g *Game) splash(t *Tower, target *Enemy, damage float64) {
+	tt := TowerTypes[t.Kind]
+	for _, e := range g.Enemies {
+		if e == target || e.HP <= 0 || e.Burrowed > 0 {
+			continue
+		}
+		if math.Hypot(e.X-target.X, e.Y-target.Y) <= tt.Splash {
+			g.damageEnemy(e, damage, t)
+		}
+	}
+	g.Explosions = append(g.Explosions, &Explosion{X: target.X, Y: target.Y, Radius: tt.Splash, TTL: ExplosionDuration, Color: tt.LaserColor})
+}
+
+// updateExplosions ages explosion rings and removes finished ones
+func (g *Game) updateExplosions() {
+	alive := g.Explosions[:0]
+	for _, x := range g.Explosions {
+		x.TTL--
+		if x.TTL > 0 {
+			alive = append(alive, x)
+		}
+	}
+	clear(g.Explosions[len(alive):])
+	g.Explosions = alive
+}
@@ -0,0 +1,15 @@
+package game
+
+// Sprinting returns true if an enemy is on its final sprint
+func (e *Enemy) Sprinting() bool {
+	et := EnemyTypes[e.Kind]
+	return et.SprintSteps > 0 && e.stepsLeft() <= et.SprintSteps
+}
+
+// sprintFactor returns an enemy's speed multiplier from its final sprint
+func (e *Enemy) sprintFactor() float64 {
+	if !e.Sprinting() {
+		return 1
+	}
+	return EnemyTypes[e.Kind].SprintSpeed
+}
@@ -0,0 +1,10 @@
+package game
+
+// creditKill records a kill for the tower that dealt the lethal blow. Kinds
+// that level up gain a permanent damage stack, up to their cap.
+func (t *Tower) creditKill() {
+	t.Kills++
+	if t.KillStacks < TowerTypes[t.Kind].MaxStacks {
+		t.KillStacks++
+	}
+}
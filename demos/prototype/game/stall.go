@@ -0,0 +1,24 @@
+package game
+
+// With the path fully blocked, enemies have nowhere to spawn. Rather than
+// quietly dropping them (and the wave looking finished), spawning holds
+// until there's a path again, and the player is told why.
+
+const (
+	StallGrace         = 5 * TPS // Ticks stalled before the warning starts demanding attention
+	StallAlarmInterval = TPS     // Ticks between alarm beeps after the grace period
+)
+
+// updateSpawnStall tracks how long spawning has been held up by a blocked
+// path. Returns true if it's held up now.
+func (g *Game) updateSpawnStall() bool {
+	if !g.PathBlocked {
+		g.SpawnStall = 0
+		return false
+	}
+	g.SpawnStall++
+	if g.SpawnStall >= StallGrace && (g.SpawnStall-StallGrace)%StallAlarmInterval == 0 {
+		g.Effects.Alarm()
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package game
+
+const (
+	StunImmunity   = 2 * TPS // Ticks after a stun wears off before another can land
+	BossStunFactor = 0.5     // Bosses shake off stuns this much faster
+)
+
+// stun halts an enemy for a number of ticks, unless it's already stunned or
+// still shaking off the last stun, so no enemy can be locked down for good
+func (e *Enemy) stun(ticks int) {
+	if e.Stunned > 0 || e.stunImmune > 0 {
+		return
+	}
+	if EnemyTypes[e.Kind].Boss {
+		ticks = int(float64(ticks) * BossStunFactor)
+	}
+	e.Stunned = ticks
+}
+
+// updateStun counts down an enemy's stun, then its immunity to the next.
+// Returns true if it's stunned this tick, so it doesn't move.
+func (e *Enemy) updateStun() bool {
+	if e.Stunned > 0 {
+		e.Stunned--
+		if e.Stunned == 0 {
+			e.stunImmune = StunImmunity
+		}
+		return true
+	}
+	if e.stunImmune > 0 {
+		e.stunImmune--
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package game
+
+import "testing"
+
+func TestStunnedEnemiesStandStill(t *testing.T) {
+	const stunTicks = 30
+	for _, tc := range []struct {
+		kind      EnemyKind
+		wantTicks int
+	}{
+		{kind: EnemyNormal, wantTicks: stunTicks},
+		{kind: EnemyBoss, wantTicks: int(stunTicks * BossStunFactor)},
+	} {
+		t.Run(EnemyTypes[tc.kind].Name, func(t *testing.T) {
+			g := newTestGame(t, openField...)
+			g.spawnEnemy(tc.kind)
+			e := g.Enemies[0]
+			g.updateEnemies() // Under way
+
+			e.stun(stunTicks)
+			stood := 0
+			for e.Stunned > 0 {
+				x, y := e.X, e.Y
+				g.updateEnemies()
+				if e.X != x || e.Y != y {
+					t.Fatalf("stunned enemy moved from (%v, %v) to (%v, %v)", x, y, e.X, e.Y)
+				}
+				stood++
+			}
+			if stood != tc.wantTicks {
+				t.Errorf("stood still %d ticks, want %d", stood, tc.wantTicks)
+			}
+
+			x := e.X
+			g.updateEnemies()
+			if e.X == x {
+				t.Errorf("enemy didn't move once the stun wore off")
+			}
+		})
+	}
+}
+
+func TestStunsCantChain(t *testing.T) {
+	g := newTestGame(t, openField...)
+	g.spawnEnemy(EnemyNormal)
+	e := g.Enemies[0]
+	e.stun(1)
+	g.updateEnemies() // Wears off
+
+	for tick := range StunImmunity {
+		e.stun(TPS)
+		if e.Stunned > 0 {
+			t.Fatalf("stunned again %d ticks after the last stun", tick)
+		}
+		g.updateEnemies()
+	}
+	e.stun(TPS)
+	if e.Stunned != TPS {
+		t.Errorf("Stunned = %d after immunity ran out, want %d", e.Stunned, TPS)
+	}
+}
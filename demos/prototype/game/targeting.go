@@ -0,0 +1,60 @@
+package game
+
+import "math"
+
+// TargetMode is how a tower chooses among the enemies it could shoot
+type TargetMode int
+
+const (
+	TargetFirst     TargetMode = iota // Furthest along its path (closest to base)
+	TargetLast                        // Least far along its path
+	TargetClosest                     // Nearest the tower
+	TargetStrongest                   // Most HP plus shield left
+	TargetWeakest                     // Least HP plus shield left
+	numTargetModes
+)
+
+var targetModeNames = [numTargetModes]string{"First", "Last", "Closest", "Strongest", "Weakest"}
+
+func (m TargetMode) String() string {
+	return targetModeNames[m]
+}
+
+// Next returns the mode after m, wrapping around
+func (m TargetMode) Next() TargetMode {
+	return (m + 1) % numTargetModes
+}
+
+// selectTarget picks a new target: the valid enemy that best fits the
+// tower's targeting mode. Ties go to the enemy that spawned first.
+func (g *Game) selectTarget(t *Tower, stats TowerStats) *Enemy {
+	cx, cy := CellCenter(t.X, t.Y)
+	health := func(e *Enemy) float64 { return e.HP + e.Shield }
+	var target *Enemy
+	for _, e := range g.Enemies {
+		if !g.CanTarget(t, stats, e) {
+			continue
+		}
+		if target == nil {
+			target = e
+			continue
+		}
+		var better bool
+		switch t.TargetMode {
+		case TargetFirst:
+			better = e.stepsLeft() < target.stepsLeft()
+		case TargetLast:
+			better = e.stepsLeft() > target.stepsLeft()
+		case TargetClosest:
+			better = math.Hypot(e.X-cx, e.Y-cy) < math.Hypot(target.X-cx, target.Y-cy)
+		case TargetStrongest:
+			better = health(e) > health(target)
+		case TargetWeakest:
+			better = health(e) < health(target)
+		}
+		if better {
+			target = e
+		}
+	}
+	return target
+}
@@ -0,0 +1,34 @@
+package game
+
+import "testing"
+
+func TestTowersKeepTheirTargetWhileItStaysValid(t *testing.T) {
+	g := newTestGame(t, openField...)
+	tower := buildTower(t, g, 5, 1, KindBasic)
+	first := placeEnemy(g, 5, 3)
+	second := placeEnemy(g, 6, 3)
+	for _, e := range []*Enemy{first, second} {
+		e.HP = 1e6 // Survives every shot
+	}
+	first.PathIndex, second.PathIndex = 3, 2
+	g.updateTowers()
+	if tower.Target != first {
+		t.Fatalf("tower didn't pick the enemy furthest along")
+	}
+
+	// The other enemy pulls ahead, but the tower stays on its target
+	second.PathIndex = 8
+	tower.Cooldown = 0
+	g.updateTowers()
+	if tower.Target != first {
+		t.Errorf("tower switched to an enemy that pulled ahead")
+	}
+
+	// Once the target leaves range the tower moves on
+	first.X, first.Y = CellCenter(10, 5)
+	tower.Cooldown = 0
+	g.updateTowers()
+	if tower.Target != second {
+		t.Errorf("tower kept a target out of range")
+	}
+}
@@ -0,0 +1,37 @@
+package game
+
+import "math"
+
+// recalculateSlow rebuilds the per-cell slow map from finished tar pits.
+// Overlapping fields don't stack: a cell keeps the strongest slow on it.
+func (g *Game) recalculateSlow() {
+	g.Slow = newCells[float64](g.GridWidth(), g.GridHeight())
+	for _, t := range g.Towers {
+		slow := TowerTypes[t.Kind].Slow
+		if slow == 0 || t.BuildTimer > 0 {
+			continue
+		}
+		tx, ty := CellCenter(t.X, t.Y)
+		rng := g.EffectiveStats(t).Range
+		for y := range g.Slow {
+			for x := range g.Slow[y] {
+				if !g.isWalkable(x, y) {
+					continue
+				}
+				px, py := CellCenter(x, y)
+				if math.Hypot(px-tx, py-ty) <= rng {
+					g.Slow[y][x] = max(g.Slow[y][x], slow)
+				}
+			}
+		}
+	}
+}
+
+// slowAt returns the fraction of speed lost at a pixel position (0 = none)
+func (g *Game) slowAt(px, py float64) float64 {
+	x, y := int(px)/CellSize, int(py)/CellSize
+	if !g.InGrid(x, y) {
+		return 0
+	}
+	return g.Slow[y][x]
+}
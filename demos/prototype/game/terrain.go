@@ -0,0 +1,44 @@
+package game
+
+const (
+	MudSpeed  = 0.5 // Enemy speed multiplier on mud
+	RoadSpeed = 1.5 // Enemy speed multiplier on road
+
+	// A* step costs are proportional to the time it takes to cross a cell
+	GroundStepCost = 6
+	MudStepCost    = 12 // GroundStepCost / MudSpeed
+	RoadStepCost   = 4  // GroundStepCost / RoadSpeed
+	MinStepCost    = RoadStepCost
+)
+
+// tileSpeed returns the enemy speed multiplier on a tile
+func tileSpeed(tile TileType) float64 {
+	switch tile {
+	case TileMud:
+		return MudSpeed
+	case TileRoad:
+		return RoadSpeed
+	}
+	return 1
+}
+
+// stepCost returns the A* cost of stepping onto a cell, so routes prefer
+// terrain enemies cross quickly
+func (g *Game) stepCost(p Point) int {
+	switch g.Grid[p.Y][p.X] {
+	case TileMud:
+		return MudStepCost
+	case TileRoad:
+		return RoadStepCost
+	}
+	return GroundStepCost
+}
+
+// terrainSpeedAt returns the speed multiplier of the tile under a pixel position
+func (g *Game) terrainSpeedAt(px, py float64) float64 {
+	x, y := int(px)/CellSize, int(py)/CellSize
+	if !g.InGrid(x, y) {
+		return 1
+	}
+	return tileSpeed(g.Grid[y][x])
+}
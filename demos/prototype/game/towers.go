@@ -0,0 +1,283 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+const (
+	FacingStep      = math.Pi / 4 // Rotation per scroll-wheel notch (8 directions)
+	ManualFireBonus = 2.0         // Damage multiplier for player-triggered shots
+	ConsumedRefund  = 4           // Used-up towers refund 1/ConsumedRefund of their cost
+	TowerBuildTime  = 90          // Ticks after placement before a tower can act
+
+	DefaultLaserWidth = 2 // Shot line width for towers that don't set their own
+)
+
+// TowerKind identifies a type of tower
+type TowerKind int
+
+const (
+	KindBasic       TowerKind = iota // Hitscan laser
+	KindMiner                        // Seeds explosive traps on the path
+	KindFlame                        // Short-range cone hitting everything it faces
+	KindGlassCannon                  // Huge single shots, consumed after a few
+	KindShredder                     // Weak shots that strip enemy armor
+	KindTarPit                       // Slows enemies on nearby path cells; never shoots
+	KindBouncePad                    // Shoves enemies stepping next to it back a cell
+	KindStunner                      // Light shots that briefly stop enemies in their tracks
+	KindHunter                       // Grows permanently stronger with every kill
+	KindFocus                        // Continuous beam that ramps up damage on one target
+	KindSniper                       // Long range, heavy hits, slow to reload
+	KindRapid                        // Short range, light hits, fires constantly
+	KindSplash                       // Shots burst, hitting everything near the target
+	KindFrost                        // Harmless shots that slow the target for a while
+	KindCannon                       // Heavy shells that take time to reach their target
+)
+
+// TowerStats are a tower's combat numbers
+type TowerStats struct {
+	Range    float64 // Pixels
+	Damage   float64 // Per shot (per trap for miners)
+	Cooldown int     // Ticks between shots
+}
+
+// DPS returns damage per second. A tower fires on the tick its cooldown
+// reaches zero, so shots land every Cooldown+1 ticks.
+func (s TowerStats) DPS() float64 {
+	return s.Damage * TPS / float64(s.Cooldown+1)
+}
+
+// TowerType describes everything shared by towers of one kind
+type TowerType struct {
+	Name        string
+	Special     string // What sets it apart, in a few words, for the build bar
+	Cost        int
+	Color       color.RGBA
+	LaserColor  color.RGBA // Shot color; zero for the palette's laser color
+	LaserWidth  float32    // Shot line width; 0 for DefaultLaserWidth
+	Arc         float64    // Half-angle of the attack cone in radians; 0 = omnidirectional
+	Shots       int        // Shots before the tower is used up; 0 = unlimited
+	Shred       float64    // Armor stripped from the target per hit
+	Slow        float64    // Fraction of speed lost on path cells in range; 0 = none
+	Bounce      bool       // Shoves enemies back a cell instead of shooting
+	Traps       bool       // Lays traps on the path instead of shooting
+	Stun        int        // Ticks each hit stops the target for; 0 = none
+	StackDamage float64    // Damage added per kill stack
+	MaxStacks   int        // Most kill stacks it can gain; 0 = doesn't level up
+	Ramp        float64    // Damage bonus gained per consecutive hit on one target
+	MaxRamp     float64    // Cap on the ramp bonus; 0 = doesn't ramp
+	Splash      float64    // Radius around the target in pixels that shots also hit; 0 = single target
+	Chill       float64    // Fraction of speed each hit takes from the target; 0 = none
+	ChillTicks  int        // Ticks a chill lasts
+	ShellSpeed  float64    // Pixels per tick its shells fly; 0 = shots hit instantly
+	TowerStats
+}
+
+// TowerTypes holds the base stats of each tower kind, indexed by TowerKind
+var TowerTypes = []TowerType{
+	KindBasic: {
+		Name:       "Basic",
+		Special:    "Hitscan laser",
+		Cost:       TowerCost,
+		Color:      color.RGBA{R: 50, G: 200, B: 50, A: 255},
+		TowerStats: TowerStats{Range: TowerRange, Damage: TowerDamage, Cooldown: TowerCooldown},
+	},
+	KindMiner: {
+		Name:       "Miner",
+		Special:    "Lays traps",
+		Cost:       40,
+		Color:      color.RGBA{R: 200, G: 140, B: 40, A: 255},
+		Traps:      true,
+		TowerStats: TowerStats{Range: 100, Damage: 40, Cooldown: 150},
+	},
+	KindFlame: {
+		Name:       "Flame",
+		Special:    "Cone, hits all",
+		Cost:       35,
+		Color:      color.RGBA{R: 230, G: 80, B: 30, A: 255},
+		Arc:        math.Pi / 4,
+		LaserColor: color.RGBA{R: 255, G: 120, B: 20, A: 255},
+		LaserWidth: 3,
+		TowerStats: TowerStats{Range: 90, Damage: 4, Cooldown: 10},
+	},
+	KindGlassCannon: {
+		Name:       "Glass Cannon",
+		Special:    "Few huge shots",
+		Cost:       60,
+		Color:      color.RGBA{R: 170, G: 230, B: 255, A: 255},
+		Shots:      6,
+		LaserColor: color.RGBA{R: 200, G: 245, B: 255, A: 255},
+		LaserWidth: 4,
+		TowerStats: TowerStats{Range: 160, Damage: 250, Cooldown: 120},
+	},
+	KindShredder: {
+		Name:       "Shredder",
+		Special:    "Strips armor",
+		Cost:       30,
+		Color:      color.RGBA{R: 140, G: 90, B: 160, A: 255},
+		Shred:      3,
+		LaserColor: color.RGBA{R: 200, G: 120, B: 255, A: 255},
+		LaserWidth: 1.5,
+		TowerStats: TowerStats{Range: 110, Damage: 5, Cooldown: 20},
+	},
+	KindTarPit: {
+		Name:       "Tar Pit",
+		Special:    "Slows path",
+		Cost:       25,
+		Color:      color.RGBA{R: 70, G: 55, B: 40, A: 255},
+		Slow:       0.5,
+		TowerStats: TowerStats{Range: 70},
+	},
+	KindBouncePad: {
+		Name:       "Bounce Pad",
+		Special:    "Shoves back",
+		Cost:       30,
+		Color:      color.RGBA{R: 230, G: 200, B: 60, A: 255},
+		Bounce:     true,
+		TowerStats: TowerStats{Range: CellSize, Cooldown: 90}, // Range covers the four neighboring cells
+	},
+	KindStunner: {
+		Name:       "Stunner",
+		Special:    "Stuns briefly",
+		Cost:       45,
+		Color:      color.RGBA{R: 240, G: 230, B: 140, A: 255},
+		Stun:       TPS / 2,
+		LaserColor: color.RGBA{R: 255, G: 250, B: 180, A: 255},
+		TowerStats: TowerStats{Range: 100, Damage: 2, Cooldown: 75},
+	},
+	KindHunter: {
+		Name:        "Hunter",
+		Special:     "Grows per kill",
+		Cost:        50,
+		Color:       color.RGBA{R: 180, G: 60, B: 60, A: 255},
+		StackDamage: 0.5,
+		MaxStacks:   40,
+		LaserColor:  color.RGBA{R: 255, G: 90, B: 90, A: 255},
+		TowerStats:  TowerStats{Range: 120, Damage: 6, Cooldown: 25},
+	},
+	KindFocus: {
+		Name:       "Focus Beam",
+		Special:    "Ramps on one target",
+		Cost:       55,
+		Color:      color.RGBA{R: 90, G: 120, B: 230, A: 255},
+		Ramp:       0.1,
+		MaxRamp:    3,
+		LaserColor: color.RGBA{R: 120, G: 160, B: 255, A: 255},
+		LaserWidth: 1.5,
+		TowerStats: TowerStats{Range: 110, Damage: 1, Cooldown: 5},
+	},
+	KindSniper: {
+		Name:       "Sniper",
+		Special:    "Long range",
+		Cost:       50,
+		Color:      color.RGBA{R: 60, G: 90, B: 60, A: 255},
+		LaserColor: color.RGBA{R: 220, G: 255, B: 220, A: 255},
+		TowerStats: TowerStats{Range: 220, Damage: 40, Cooldown: 100},
+	},
+	KindRapid: {
+		Name:       "Rapid",
+		Special:    "Fast fire",
+		Cost:       35,
+		Color:      color.RGBA{R: 230, G: 150, B: 200, A: 255},
+		LaserColor: color.RGBA{R: 255, G: 190, B: 230, A: 255},
+		LaserWidth: 1,
+		TowerStats: TowerStats{Range: 90, Damage: 3, Cooldown: 7},
+	},
+	KindSplash: {
+		Name:       "Splash",
+		Special:    "Area damage",
+		Cost:       60,
+		Color:      color.RGBA{R: 200, G: 100, B: 40, A: 255},
+		Splash:     45,
+		LaserColor: color.RGBA{R: 255, G: 160, B: 60, A: 255},
+		LaserWidth: 2,
+		TowerStats: TowerStats{Range: 110, Damage: 12, Cooldown: 60},
+	},
+	KindFrost: {
+		Name:       "Frost",
+		Special:    "Chills target",
+		Cost:       35,
+		Color:      color.RGBA{R: 150, G: 210, B: 255, A: 255},
+		Chill:      0.4,
+		ChillTicks: 2 * TPS,
+		LaserColor: color.RGBA{R: 180, G: 230, B: 255, A: 255},
+		TowerStats: TowerStats{Range: 110, Cooldown: 40},
+	},
+	KindCannon: {
+		Name:       "Cannon",
+		Special:    "Slow shells",
+		Cost:       45,
+		Color:      color.RGBA{R: 90, G: 90, B: 80, A: 255},
+		LaserColor: color.RGBA{R: 255, G: 220, B: 120, A: 255},
+		ShellSpeed: 5,
+		TowerStats: TowerStats{Range: 150, Damage: 30, Cooldown: 70},
+	},
+}
+
+// EffectiveStats returns the stats a tower actually fights with
+func (g *Game) EffectiveStats(t *Tower) TowerStats {
+	tt := TowerTypes[t.Kind]
+	stats := tt.TowerStats
+	levels := float64(t.Level - 1)
+	stats.Damage *= 1 + LevelDamageBonus*levels
+	stats.Range *= 1 + LevelRangeBonus*levels
+	stats.Damage += tt.StackDamage * float64(t.KillStacks)
+	return stats
+}
+
+// facePath returns the facing from a cell toward the nearest path cell, so a
+// new directional tower starts out pointing at the enemies
+func (g *Game) facePath(x, y int) float64 {
+	cx, cy := CellCenter(x, y)
+	best := math.Inf(1)
+	facing := 0.0
+	for _, p := range g.pathCells() {
+		px, py := CellCenter(p.X, p.Y)
+		if d := math.Hypot(px-cx, py-cy); d < best {
+			best = d
+			facing = math.Atan2(py-cy, px-cx)
+		}
+	}
+	return facing
+}
+
+// inArc returns true if a pixel position lies within a directional tower's cone
+func inArc(t *Tower, arc, px, py float64) bool {
+	cx, cy := CellCenter(t.X, t.Y)
+	diff := math.Atan2(py-cy, px-cx) - t.Facing
+	diff = math.Remainder(diff, 2*math.Pi) // Normalize to -Pi..Pi
+	return math.Abs(diff) <= arc
+}
+
+// fireCone damages every visible enemy in range inside the tower's cone
+func (g *Game) fireCone(t *Tower, stats TowerStats) {
+	cx, cy := CellCenter(t.X, t.Y)
+	arc := TowerTypes[t.Kind].Arc
+	hit := false
+	for _, e := range g.Enemies {
+		if e.HP <= 0 || e.Burrowed > 0 || math.Hypot(e.X-cx, e.Y-cy) > stats.Range {
+			continue
+		}
+		if !inArc(t, arc, e.X, e.Y) || !g.canSee(t.X, t.Y, e.X, e.Y) {
+			continue
+		}
+		g.damageEnemy(e, stats.Damage, t)
+		g.addLaser(t.Kind, cx, cy, e.X, e.Y)
+		hit = true
+	}
+	if hit {
+		t.Cooldown = stats.Cooldown
+		g.Effects.Shot()
+	}
+}
+
+// consumeTower removes a tower that has used up its shots, with a small refund
+func (g *Game) consumeTower(t *Tower) {
+	refund := TowerTypes[t.Kind].Cost / ConsumedRefund
+	g.Resources += refund
+	g.deleteTower(t)
+	cx, cy := CellCenter(t.X, t.Y)
+	g.AddFloatingText(cx, cy, fmt.Sprintf("spent +%d", refund))
+}
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"slices"
+	"strings"
+)
+
+const MaxTowerTypes = 16 // Most tower kinds the build bar has room for
+
+// LoadTowerTypes reads custom tower definitions from a JSON array and
+// returns the built-in tower table with them applied: a definition whose
+// name matches a built-in kind replaces it, any other is added as a new
+// kind. Field names match TowerType (case-insensitively), with Range,
+// Damage, and Cooldown at the top level and colors as {"R":..,"G":..,"B":..,"A":..}.
+// A missing file just gives the built-ins, as does any error.
+func LoadTowerTypes(path string) ([]TowerType, error) {
+	types := slices.Clone(TowerTypes)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return types, nil
+	}
+	if err != nil {
+		return TowerTypes, err
+	}
+	var defs []TowerType
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return TowerTypes, fmt.Errorf("%s: %w", path, err)
+	}
+	for i, def := range defs {
+		if err := def.validate(); err != nil {
+			return TowerTypes, fmt.Errorf("%s: tower %d (%q): %w", path, i, def.Name, err)
+		}
+		j := slices.IndexFunc(types, func(t TowerType) bool { return strings.EqualFold(t.Name, def.Name) })
+		if j >= 0 {
+			types[j] = def
+		} else {
+			types = append(types, def)
+		}
+	}
+	if len(types) > MaxTowerTypes {
+		return TowerTypes, fmt.Errorf("%s: %d tower kinds, at most %d fit", path, len(types), MaxTowerTypes)
+	}
+	return types, nil
+}
+
+// validate checks a tower definition has what the game needs to use it
+func (t TowerType) validate() error {
+	behaviors := 0
+	for _, on := range []bool{t.Traps, t.Bounce, t.Slow > 0, t.Arc > 0} {
+		if on {
+			behaviors++
+		}
+	}
+	switch {
+	case t.Name == "":
+		return errors.New("name is required")
+	case t.Cost <= 0:
+		return errors.New("cost must be positive")
+	case t.Range <= 0:
+		return errors.New("range must be positive")
+	case t.Damage < 0 || t.Cooldown < 0 || t.Shots < 0 || t.Shred < 0 || t.Stun < 0 || t.LaserWidth < 0 ||
+		t.StackDamage < 0 || t.MaxStacks < 0 || t.Ramp < 0 || t.MaxRamp < 0 || t.Splash < 0 || t.ChillTicks < 0 || t.ShellSpeed < 0:
+		return errors.New("damage, cooldown, shots, shred, stun, laser width, stacks, ramp, splash, chill ticks, and shell speed can't be negative")
+	case t.Slow < 0 || t.Slow >= 1:
+		return errors.New("slow must be from 0 up to (not including) 1")
+	case t.Chill < 0 || t.Chill >= 1:
+		return errors.New("chill must be from 0 up to (not including) 1")
+	case t.Arc < 0 || t.Arc > math.Pi:
+		return errors.New("arc must be from 0 to pi")
+	case behaviors > 1:
+		return errors.New("only one of traps, bounce, slow, and arc can be set")
+	case t.Damage == 0 && t.Slow == 0 && t.Chill == 0 && !t.Bounce:
+		return errors.New("damage must be positive unless the tower slows, chills, or bounces")
+	case t.Color.A == 0:
+		return errors.New("color is required (with nonzero A)")
+	}
+	return nil
+}
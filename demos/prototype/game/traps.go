@@ -0,0 +1,94 @@
+package game
+
+import "math"
+
+const (
+	TrapTTL          = 600  // Ticks an unsprung trap stays armed
+	TrapRadius       = 50.0 // Blast radius in pixels
+	MaxTrapsPerMiner = 3    // Armed traps a single miner can have out
+)
+
+// Trap is an armed cell on the path that explodes when an enemy steps on it.
+// Traps don't block pathing.
+type Trap struct {
+	Cell   Point
+	Damage float64
+	TTL    int    // Ticks until it disarms
+	Owner  *Tower // Miner that laid it
+}
+
+// trapAt returns the armed trap on a cell, or nil
+func (g *Game) trapAt(c Point) *Trap {
+	for _, tr := range g.Traps {
+		if tr.Cell == c {
+			return tr
+		}
+	}
+	return nil
+}
+
+// seedTrap has a miner arm the first untrapped path cell in its range, so
+// incoming enemies reach it before anything further along. Returns false if
+// there was nowhere to put one.
+func (g *Game) seedTrap(t *Tower, stats TowerStats) bool {
+	owned := 0
+	for _, tr := range g.Traps {
+		if tr.Owner == t {
+			owned++
+		}
+	}
+	if owned >= MaxTrapsPerMiner || g.PathBlocked {
+		return false
+	}
+	tx, ty := CellCenter(t.X, t.Y)
+	for _, c := range g.pathCells() {
+		if tile := g.Grid[c.Y][c.X]; tile == TileSpawn || tile == TileBase || g.trapAt(c) != nil {
+			continue
+		}
+		px, py := CellCenter(c.X, c.Y)
+		if math.Hypot(px-tx, py-ty) > stats.Range {
+			continue
+		}
+		g.Traps = append(g.Traps, &Trap{Cell: c, Damage: stats.Damage, TTL: TrapTTL, Owner: t})
+		return true
+	}
+	return false
+}
+
+// updateTraps springs traps enemies stepped on and disarms expired ones
+func (g *Game) updateTraps() {
+	armed := g.Traps[:0]
+	for _, tr := range g.Traps {
+		if g.enemyOnCell(tr.Cell) {
+			g.explodeTrap(tr)
+			continue
+		}
+		tr.TTL--
+		if tr.TTL > 0 {
+			armed = append(armed, tr)
+		}
+	}
+	g.Traps = armed
+}
+
+// enemyOnCell returns true if a living enemy is standing on the cell (flyers
+// and burrowers pass over and under it)
+func (g *Game) enemyOnCell(c Point) bool {
+	for _, e := range g.Enemies {
+		if e.HP > 0 && e.Burrowed == 0 && !e.Flies() && int(e.X)/CellSize == c.X && int(e.Y)/CellSize == c.Y {
+			return true
+		}
+	}
+	return false
+}
+
+// explodeTrap damages every enemy within the blast radius of a trap
+func (g *Game) explodeTrap(tr *Trap) {
+	cx, cy := CellCenter(tr.Cell.X, tr.Cell.Y)
+	for _, e := range g.Enemies {
+		if e.HP > 0 && e.Burrowed == 0 && math.Hypot(e.X-cx, e.Y-cy) <= TrapRadius {
+			g.damageEnemy(e, tr.Damage, tr.Owner)
+		}
+	}
+	g.AddFloatingText(cx, cy, "BOOM")
+}
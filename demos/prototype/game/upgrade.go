@@ -0,0 +1,40 @@
+package game
+
+const (
+	MaxTowerLevel    = 3
+	LevelDamageBonus = 0.5  // Extra fraction of base damage per level above 1
+	LevelRangeBonus  = 0.15 // Extra fraction of base range per level above 1
+)
+
+// UpgradeCost returns what taking a tower to its next level costs: its
+// kind's cost times its current level
+func UpgradeCost(t *Tower) int {
+	return TowerTypes[t.Kind].Cost * t.Level
+}
+
+// UpgradeTower raises the level of the tower on a cell, if it isn't maxed
+// out and can be paid for. Returns false if nothing changed.
+func (g *Game) UpgradeTower(x, y int) bool {
+	t := g.TowerAt(x, y)
+	if t == nil {
+		return false
+	}
+	if t.Level >= MaxTowerLevel {
+		g.ShowMessage("%s is already max level", TowerTypes[t.Kind].Name)
+		return false
+	}
+	cost := UpgradeCost(t)
+	if g.Resources < cost {
+		g.ShowMessage("Upgrade costs %d", cost)
+		return false
+	}
+	g.Resources -= cost
+	t.Invested += cost
+	t.Level++
+	g.CoverageDirty = true // Its range grew
+	if TowerTypes[t.Kind].Slow > 0 {
+		g.recalculateSlow()
+	}
+	g.ShowMessage("%s upgraded to level %d", TowerTypes[t.Kind].Name, t.Level)
+	return true
+}
@@ -0,0 +1,10 @@
+package game
+
+// Void cells aren't part of the board at all: nothing walks, builds, or is
+// drawn there, so a map can be L-shaped or have holes in it. Unlike walls
+// they don't block line of sight; shots carry over them.
+
+// OnBoard returns true if a cell is on the grid and not void
+func (g *Game) OnBoard(x, y int) bool {
+	return g.InGrid(x, y) && g.Grid[y][x] != TileVoid
+}
@@ -0,0 +1,29 @@
+package game
+
+// WaveHP returns the health (and shields) the board still has to chew
+// through this wave: what live enemies have left plus the full health of
+// those still to spawn
+func (g *Game) WaveHP() float64 {
+	hp := 0.0
+	for _, e := range g.Enemies {
+		if e.HP > 0 {
+			hp += e.HP + e.Shield
+		}
+	}
+	for n := g.WaveSpawned; n < g.WaveSpawned+g.EnemiesThisWave; n++ {
+		kind := g.WaveEnemyKind(g.CurrentWave, n)
+		hp += MaxHP(kind) + EnemyTypes[kind].ShieldMax
+	}
+	return hp
+}
+
+// BoardDPS returns the combined damage per second of every finished tower
+func (g *Game) BoardDPS() float64 {
+	dps := 0.0
+	for _, t := range g.Towers {
+		if t.BuildTimer == 0 {
+			dps += g.EffectiveStats(t).DPS()
+		}
+	}
+	return dps
+}
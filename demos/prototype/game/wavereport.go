@@ -0,0 +1,9 @@
+package game
+
+// recordTowerDamage adds damage a tower dealt to its kind's total for the wave
+func (g *Game) recordTowerDamage(t *Tower, dealt float64) {
+	if g.waveDamage == nil {
+		g.waveDamage = make(map[TowerKind]float64)
+	}
+	g.waveDamage[t.Kind] += dealt
+}
@@ -0,0 +1,182 @@
+package game
+
+import "fmt"
+
+const (
+	SurgeThreshold  = 0.75 // Min average share of path left unwalked by enemies killed this wave
+	SurgeMinKills   = 3    // Kills needed this wave before judging performance
+	SurgeSize       = 2    // Extra enemies added per surge
+	SurgeMaxPerWave = 3    // Cap on surges in a single wave
+
+	MegaWaveInterval     = 10  // Every Nth wave in endless mode is a mega wave
+	MegaWaveMultiplier   = 3   // Mega waves have this many times the enemies
+	MegaBountyMultiplier = 2   // Kill reward multiplier during mega waves
+	MegaFlashDuration    = 180 // Ticks the board flashes when a mega wave starts
+
+	MaxLookahead = 3 // Most upcoming waves the preview panel can show
+
+	WaveClearBonus   = 10 // Resources for clearing any wave
+	WaveClearPerWave = 5  // Extra clear bonus per wave number
+	CleanWaveBonus   = 15 // Extra for a wave where no enemy got near the base; 0 to disable
+
+	WaveProgressHeight = 4 // Pixels
+)
+
+// updateWave runs wave timers, spawns enemies, and advances once a wave is cleared
+func (g *Game) updateWave() {
+	if g.WaveDelay > 0 {
+		g.WaveDelay--
+	} else if g.EnemiesThisWave > 0 {
+		// Spawn enemies for current wave, once there's a path for them
+		if g.updateSpawnStall() {
+			return
+		}
+		g.spawnTimer--
+		if g.spawnTimer <= 0 {
+			g.spawnEnemy(g.WaveEnemyKind(g.CurrentWave, g.WaveSpawned))
+			g.WaveSpawned++
+			g.EnemiesThisWave--
+			g.spawnTimer = SpawnInterval
+		}
+	} else if len(g.Enemies) == 0 {
+		// Wave complete, all enemies dead
+		g.grantWaveClearBonus()
+		if g.CurrentWave >= TotalWaves && !g.Endless {
+			// All waves complete - WIN!
+			g.State = StateWon
+		} else {
+			g.startNextWave()
+		}
+	}
+}
+
+// grantWaveClearBonus pays the lump sum for clearing the current wave, plus
+// CleanWaveBonus if no enemy ever set off the base alert. Paid at most once
+// per wave.
+func (g *Game) grantWaveClearBonus() {
+	if g.clearedWave >= g.CurrentWave {
+		return
+	}
+	g.clearedWave = g.CurrentWave
+	bonus := WaveClearBonus + WaveClearPerWave*g.CurrentWave
+	label := fmt.Sprintf("Wave clear +%d", bonus)
+	if !g.waveNearLeak && CleanWaveBonus > 0 {
+		bonus += CleanWaveBonus
+		label = fmt.Sprintf("Clean wave +%d", bonus)
+	}
+	g.Resources += bonus
+	cx, cy := CellCenter(g.Bases[0].X, g.Bases[0].Y)
+	g.AddFloatingText(cx, cy, label)
+}
+
+// WaveSize returns how many enemies a wave spawns (before any surge)
+func (g *Game) WaveSize(wave int) int {
+	if g.IsBossWave(wave) {
+		return 1
+	}
+	n := EnemiesPerWave
+	if wave > 1 {
+		n += wave // More enemies each wave
+	}
+	if g.Daily {
+		n += dailyWaveBonus(g.Seed, wave)
+	}
+	if g.IsMegaWave(wave) {
+		n *= MegaWaveMultiplier
+	}
+	return n
+}
+
+// IsMegaWave returns true if a wave is an endless-mode milestone
+func (g *Game) IsMegaWave(wave int) bool {
+	return g.Endless && wave%MegaWaveInterval == 0
+}
+
+// startNextWave moves to the next wave and starts its countdown
+func (g *Game) startNextWave() {
+	g.CurrentWave++
+	g.EnemiesThisWave = g.WaveSize(g.CurrentWave)
+	g.WaveDelay = WaveDelay
+	if g.AutoStartWaves {
+		g.WaveDelay = AutoWaveDelay
+	}
+	g.WaveSpawned = 0
+
+	g.MegaWave = g.IsMegaWave(g.CurrentWave)
+	if g.MegaWave {
+		g.MegaFlash = MegaFlashDuration
+		g.ShowMessage("MEGA WAVE %d incoming! Bounty x%d", g.CurrentWave, MegaBountyMultiplier)
+	}
+	if g.IsBossWave(g.CurrentWave) {
+		g.ShowMessage("BOSS WAVE: one huge enemy incoming. Kill it to win!")
+	}
+
+	g.WaveKills = 0
+	g.LastWaveDamage, g.waveDamage = g.waveDamage, nil
+	g.surgeKills = 0
+	g.surgeProgress = 0
+	g.WaveSurges = 0
+	g.waveNearLeak = false
+}
+
+// killReward returns the bounty for an enemy of a kind spawned now
+func (g *Game) killReward(kind EnemyKind) int {
+	reward := KillReward + EnemyTypes[kind].RewardBonus
+	if g.MegaWave {
+		return reward * MegaBountyMultiplier
+	}
+	return reward
+}
+
+// recordWaveKill tracks how far toward the base a killed enemy got, measured
+// like DangerRatio so a reroute's fresh PathIndex doesn't count as a retreat
+func (g *Game) recordWaveKill(e *Enemy) {
+	g.WaveKills++
+	g.surgeKills++
+	g.surgeProgress += g.DangerRatio(e)
+	g.checkSurge()
+}
+
+// checkSurge adds reinforcements to the current wave when the player is
+// killing enemies long before they get anywhere near the base
+func (g *Game) checkSurge() {
+	if !g.SurgeEnabled || g.WaveSurges >= SurgeMaxPerWave || g.surgeKills < SurgeMinKills {
+		return
+	}
+	performance := 1 - g.surgeProgress/float64(g.surgeKills)
+	if performance < SurgeThreshold {
+		return
+	}
+	g.EnemiesThisWave += SurgeSize
+	g.WaveSurges++
+	// Judge the next surge on fresh kills only
+	g.surgeKills = 0
+	g.surgeProgress = 0
+	g.ShowMessage("SURGE! +%d enemies", SurgeSize)
+}
+
+// WaveComposition returns how many enemies of each kind a wave spawns (before
+// any surge). It walks the same WaveSize and WaveEnemyKind that updateWave
+// spawns from, so the preview always matches what actually arrives.
+func (g *Game) WaveComposition(wave int) []int {
+	counts := make([]int, len(EnemyTypes))
+	for n := range g.WaveSize(wave) {
+		counts[g.WaveEnemyKind(wave, n)]++
+	}
+	return counts
+}
+
+// PreviewWaves returns the n waves the preview panel covers: starting with
+// the incoming wave during its countdown, otherwise with the one after the
+// current wave
+func (g *Game) PreviewWaves(n int) []int {
+	first := g.CurrentWave + 1
+	if g.WaveDelay > 0 {
+		first = g.CurrentWave
+	}
+	var waves []int
+	for w := first; w < first+n && (g.Endless || w <= TotalWaves); w++ {
+		waves = append(waves, w)
+	}
+	return waves
+}
@@ -0,0 +1,16 @@
+package game
+
+import "testing"
+
+func TestWaveKillProgressSurvivesReroutes(t *testing.T) {
+	g := newTestGame(t, openField...)
+	e := placeEnemy(g, 9, 3)
+	// Rerouted one cell from the base: a fresh two-point path
+	e.Path, e.PathIndex = []Point{{X: 9, Y: 3}, g.Bases[0]}, 1
+
+	g.recordWaveKill(e)
+
+	if want := 1 - 1/float64(len(g.Paths[0])-1); g.surgeProgress != want {
+		t.Errorf("progress = %v, want %v", g.surgeProgress, want)
+	}
+}
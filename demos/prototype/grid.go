@@ -0,0 +1,19 @@
+package main
+
+// The window follows the grid: the full board at MinZoom, with the build bar
+// below it.
+
+// screenWidth returns the window width; zooming in shows part of the world
+func (g *Game) screenWidth() int {
+	return g.WorldWidth()
+}
+
+// viewHeight returns the screen height the board is shown in
+func (g *Game) viewHeight() int {
+	return g.WorldHeight()
+}
+
+// screenHeight returns the window height. The build bar sits below the board.
+func (g *Game) screenHeight() int {
+	return g.viewHeight() + BuildBarHeight
+}
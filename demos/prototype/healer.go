@@ -0,0 +1,30 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var healAuraColor = color.RGBA{R: 60, G: 220, B: 90, A: 30}
+
+var healLineColor = color.RGBA{R: 90, G: 255, B: 120, A: 160}
+
+// drawHealing surrounds each living healer with a faint aura, with a line to
+// every ally it's healing
+func (g *Game) drawHealing(screen *ebiten.Image) {
+	for _, h := range g.Enemies {
+		if !game.EnemyTypes[h.Kind].Heals || h.HP <= 0 || h.Burrowed > 0 {
+			continue
+		}
+		hx, hy := float32(h.X), float32(h.Y)
+		vector.DrawFilledCircle(screen, hx, hy, game.HealRadius, healAuraColor, true)
+		for _, e := range g.Enemies {
+			if game.Heals(h, e) {
+				vector.StrokeLine(screen, hx, hy, float32(e.X), float32(e.Y), 1.5, healLineColor, true)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var killZoneColor = color.RGBA{R: 255, G: 60, B: 60, A: 200}
+
+var killZoneFill = color.RGBA{R: 255, G: 60, B: 60, A: 35}
+
+// handleKillZoneInput arms a kill zone on ` (when off cooldown). While
+// armed, a left drag on the board sets its center and radius, and Esc
+// cancels. Returns true while it's using the left button, so it doesn't
+// also place or select.
+func (g *Game) handleKillZoneInput(wx, wy float64) bool {
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackquote) && g.State == game.StatePlaying {
+		switch {
+		case g.ZoneCooldown > 0:
+			g.ShowMessage("Kill zone ready in %ds", g.ZoneCooldown/game.TPS+1)
+		case !g.zoneArmed:
+			g.zoneArmed = true
+			g.ShowMessage("Kill zone: drag to mark it")
+		}
+	}
+	if !g.zoneArmed {
+		return false
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.zoneArmed, g.zoneDragging = false, false
+		return false
+	}
+	switch {
+	case !g.zoneDragging && g.hoverValid && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
+		g.zoneDragging = true
+		g.zoneX, g.zoneY = wx, wy
+	case g.zoneDragging && inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft):
+		g.KillZone = &game.KillZone{X: g.zoneX, Y: g.zoneY, Radius: zoneRadius(g.zoneX, g.zoneY, wx, wy), Bonus: game.KillZoneBonus, TTL: game.KillZoneDuration}
+		g.ZoneCooldown = game.KillZoneCooldown
+		g.zoneArmed, g.zoneDragging = false, false
+	}
+	return true
+}
+
+// zoneRadius returns the radius of a zone dragged from one point to another
+func zoneRadius(fromX, fromY, toX, toY float64) float64 {
+	return max(game.KillZoneMinRadius, min(game.KillZoneMaxRadius, math.Hypot(toX-fromX, toY-fromY)))
+}
+
+// drawKillZone outlines the active zone with its countdown, or the zone
+// being dragged out
+func (g *Game) drawKillZone(screen *ebiten.Image) {
+	if g.zoneDragging {
+		wx, wy := g.camera.toWorld(ebiten.CursorPosition())
+		r := float32(zoneRadius(g.zoneX, g.zoneY, wx, wy))
+		vector.StrokeCircle(screen, float32(g.zoneX), float32(g.zoneY), r, 2, killZoneColor, true)
+	}
+	z := g.KillZone
+	if z == nil {
+		return
+	}
+	vector.DrawFilledCircle(screen, float32(z.X), float32(z.Y), float32(z.Radius), killZoneFill, true)
+	vector.StrokeCircle(screen, float32(z.X), float32(z.Y), float32(z.Radius), 2, killZoneColor, true)
+	text := fmt.Sprintf("%ds", z.TTL/game.TPS+1)
+	g.drawText(screen, text, int(z.X)-g.textWidth(text)/2, int(z.Y)-g.lineHeight()/2)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// laneTint shifts a color toward red a little for each lane after the
+// first, so lanes drawn in it can be told apart
+func laneTint(c color.RGBA, lane int) color.RGBA {
+	c.G = uint8(max(int(c.G)-lane*60, 0))
+	return c
+}
+
+// drawBaseHP draws damaged bases' health bars
+func (g *Game) drawBaseHP(screen *ebiten.Image) {
+	for i, hp := range g.BaseHP {
+		if hp >= game.BaseMaxHP {
+			continue
+		}
+		b := g.Bases[i]
+		px := float32(b.X*game.CellSize) + 4
+		py := float32(b.Y*game.CellSize) + game.CellSize - 7
+		w := float32(game.CellSize - 8)
+		vector.DrawFilledRect(screen, px, py, w, 3, color.RGBA{60, 60, 60, 255}, false)
+		vector.DrawFilledRect(screen, px, py, w*float32(hp/game.BaseMaxHP), 3, dangerColor(1-hp/game.BaseMaxHP), false)
+	}
+}
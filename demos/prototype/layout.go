@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// rebuildMenu offers to queue up the last finished game's towers, so a
+// build can be tried again without placing it all by hand
+func rebuildMenu(layout []game.QueuedBuild) *Menu {
+	return &Menu{
+		Title:     fmt.Sprintf("Rebuild last layout (%d towers)?", len(layout)),
+		Escapable: true,
+		Items: []MenuItem{
+			{Label: label("No, start empty"), Select: (*Game).closeMenu},
+			{Label: label("Yes, queue it up"), Select: func(g *Game) {
+				g.closeMenu()
+				g.BuildQueueEnabled = true
+				g.BuildQueue = slices.Clone(layout)
+			}},
+		},
+	}
+}
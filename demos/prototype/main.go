@@ -1,656 +1,706 @@
 package main
 
 import (
-	"container/heap"
 	"fmt"
 	"image/color"
 	"log"
 	"math"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
 )
 
-// GameState represents the current state of the game
-type GameState int
+var highlightColor = color.RGBA{R: 255, G: 255, B: 255, A: 80}
 
-const (
-	StatePlaying GameState = iota
-	StateWon
-	StateLost
-)
+var exploredColor = color.RGBA{R: 0, G: 200, B: 255, A: 40}
 
-// Point represents a grid coordinate
-type Point struct {
-	X, Y int
-}
+var gapColor = color.RGBA{R: 255, G: 0, B: 0, A: 90}
 
-// Priority queue for A*
-type pqItem struct {
-	point    Point
-	priority int // f = g + h
-	index    int
-}
+var rangeColor = color.RGBA{R: 255, G: 255, B: 255, A: 40}
 
-type priorityQueue []*pqItem
+// Game presents a game.Game: it draws the board, turns the mouse, touch, and
+// keys into the simulation's Input, and keeps the view and session state the
+// simulation has no use for
+type Game struct {
+	*game.Game
 
-func (pq priorityQueue) Len() int           { return len(pq) }
-func (pq priorityQueue) Less(i, j int) bool { return pq[i].priority < pq[j].priority }
-func (pq priorityQueue) Swap(i, j int) {
-	pq[i], pq[j] = pq[j], pq[i]
-	pq[i].index = i
-	pq[j].index = j
-}
-func (pq *priorityQueue) Push(x any) {
-	n := len(*pq)
-	item := x.(*pqItem)
-	item.index = n
-	*pq = append(*pq, item)
-}
-func (pq *priorityQueue) Pop() any {
-	old := *pq
-	n := len(old)
-	item := old[n-1]
-	old[n-1] = nil
-	item.index = -1
-	*pq = old[0 : n-1]
-	return item
+	// Mouse state
+	hoverX, hoverY int            // Grid cell under cursor (-1 if none)
+	hoverValid     bool           // Is cursor over a valid cell?
+	touch          TouchState     // First active touch, for tablets
+	selectedKind   game.TowerKind // Kind placed by left click
+
+	// Tower selection
+	selected                   map[*game.Tower]bool // Membership only; iterate with selectedTowers for a fixed order
+	selecting                  bool                 // Dragging a selection box
+	selectStartX, selectStartY int                  // Pixel where the drag started
+	contextMenu                *ContextMenu         // Open tower action menu, or nil
+	moving                     *game.Tower          // Tower picked up to relocate, or nil
+
+	// Overlays
+	showGaps   bool           // Highlight path cells no tower can reach
+	placement  PlacementScore // Cached coverage score of the hovered placement
+	showFlow   bool           // Draw each cell's direction toward the base
+	showDanger bool           // Ring enemies by how close they are to the base
+	lookahead  int            // Upcoming waves shown in the preview panel (1..MaxLookahead)
+
+	// Kill zone being called
+	zoneArmed    bool    // Waiting for the player to drag out a zone
+	zoneDragging bool    // Left button is down, dragging out the zone
+	zoneX, zoneY float64 // Center of the zone being dragged, in world pixels
+
+	// View
+	camera      Camera        // Which part of the board the window shows
+	panDrag     PanDrag       // Right button drag in progress
+	showMinimap bool          // Show the minimap while zoomed in
+	world       *ebiten.Image // Offscreen board, drawn to the screen through the camera
+
+	// Bullet time
+	bulletTime int     // Frames of slow motion left
+	stepCarry  float64 // Fraction of a simulation step carried to the next frame
+
+	// Fast-forward
+	speed         int // Ticks per frame (times any bullet time); 0 means 1
+	stepsDeferred int // Steps the last frame put off to stay within FrameBudget
+
+	// Debug freeze frame
+	frozen  bool // Ticks only run one at a time, on request
+	editing bool // Map editor: no ticks run, and clicks paint tiles
+
+	// Menus
+	menus    []*Menu // Open menus, topmost last; the game is paused while any are open
+	quitting bool    // Quit was chosen; exit on the next update
+
+	// Session (kept across restarts)
+	settings Settings
+	sound    *soundBoard
 }
 
-const (
-	// Grid dimensions
-	GridWidth  = 20
-	GridHeight = 15
-
-	// Cell size in pixels
-	CellSize = 40
-
-	// Window dimensions
-	ScreenWidth  = GridWidth * CellSize
-	ScreenHeight = GridHeight * CellSize
-)
-
-// TileType represents what's in a cell
-type TileType int
-
-const (
-	TileEmpty TileType = iota
-	TileGround
-	TileWall
-	TileBase
-	TileSpawn
-	TileTower
-)
-
-// Colors for each tile type
-var tileColors = map[TileType]color.RGBA{
-	TileEmpty:  {R: 30, G: 30, B: 30, A: 255},    // Dark gray
-	TileGround: {R: 80, G: 60, B: 40, A: 255},    // Brown
-	TileWall:   {R: 100, G: 100, B: 100, A: 255}, // Gray
-	TileBase:   {R: 50, G: 100, B: 200, A: 255},  // Blue
-	TileSpawn:  {R: 200, G: 50, B: 50, A: 255},   // Red
-	TileTower:  {R: 50, G: 200, B: 50, A: 255},   // Green
+// restart starts a fresh game in the same mode, keeping session-wide state
+func (g *Game) restart() {
+	layout := g.LastLayout
+	switch {
+	case g.Daily:
+		g.replaceWith(game.NewDailyChallenge(g.DailyDate))
+	case g.Generated:
+		g.replaceWith(game.NewGeneratedGame(g.MapSeed))
+	case g.Tutorial:
+		g.replaceWith(game.NewTutorialGame())
+	case g.PracticeWave > 0:
+		g.replaceWith(game.NewPracticeGame(g.PracticeWave))
+	case g.MapName != "":
+		g.replaceWith(game.NewNamedGame(g.MapName))
+	case g.TwoLanes:
+		g.replaceWith(game.NewTwoLaneGame())
+	default:
+		g.replaceWith(game.NewGameSeed(g.Seed))
+	}
+	g.LastLayout = layout
+	if len(layout) > 0 {
+		g.openMenu(rebuildMenu(layout))
+	}
 }
 
-var gridLineColor = color.RGBA{R: 60, G: 60, B: 60, A: 255}
-var highlightColor = color.RGBA{R: 255, G: 255, B: 255, A: 80}
-var pathColor = color.RGBA{R: 255, G: 200, B: 50, A: 180}
-var noPathColor = color.RGBA{R: 255, G: 0, B: 0, A: 100}
-var enemyColor = color.RGBA{R: 255, G: 100, B: 100, A: 255}
-var laserColor = color.RGBA{R: 255, G: 255, B: 0, A: 255}
-
-const (
-	EnemySpeed     = 2.0   // Pixels per tick
-	SpawnInterval  = 40    // Ticks between spawns within a wave
-	EnemyRadius    = 12.0  // Visual radius
-	EnemyMaxHP     = 100.0 // Starting HP
-
-	TowerRange    = 120.0 // Pixels
-	TowerDamage   = 10.0  // Damage per shot
-	TowerCooldown = 30    // Ticks between shots
-	LaserDuration = 5     // Ticks to show laser
-
-	// Game balance
-	TotalWaves       = 5   // Waves to survive to win
-	EnemiesPerWave   = 5   // Base enemies per wave (scales with wave number)
-	WaveDelay        = 180 // Ticks between waves
-	StartingResource = 100 // Resources at game start
-	TowerCost        = 25  // Cost to place a tower
-	KillReward       = 10  // Resources earned per kill
-)
-
-// Enemy represents a moving enemy
-type Enemy struct {
-	X, Y      float64 // Position in pixels
-	PathIndex int     // Current target waypoint in path
-	Path      []Point // Enemy's own copy of the path
-	HP        float64 // Current health
+// replaceWith switches to a new game, keeping session-wide state like settings
+// and audio, and resetting the view. The window resizes if the new grid is a
+// different size.
+func (g *Game) replaceWith(ng *game.Game) {
+	resize := g.Game != nil && (ng.WorldWidth() != g.WorldWidth() || ng.WorldHeight() != g.WorldHeight())
+	*g = Game{
+		Game:        ng,
+		lookahead:   1,
+		selected:    make(map[*game.Tower]bool),
+		camera:      newCamera(ng.WorldWidth(), ng.WorldHeight()),
+		showMinimap: g.showMinimap,
+		settings:    g.settings,
+		sound:       g.sound,
+	}
+	ng.Effects = g
+	if resize {
+		ebiten.SetWindowSize(g.screenWidth(), g.screenHeight())
+	}
 }
 
-// Tower represents a placed tower
-type Tower struct {
-	X, Y     int // Grid position
-	Cooldown int // Ticks until can fire again
+// Shot plays a tower's shot
+func (g *Game) Shot() {
+	g.sound.playShot()
 }
 
-// Laser represents a visual shot effect
-type Laser struct {
-	FromX, FromY float64
-	ToX, ToY     float64
-	TTL          int // Ticks remaining to display
+// Alarm sounds the base warning beep
+func (g *Game) Alarm() {
+	g.sound.playAlarm()
 }
 
-// Game holds the game state
-type Game struct {
-	grid [GridHeight][GridWidth]TileType
-
-	// Mouse state
-	hoverX, hoverY int  // Grid cell under cursor (-1 if none)
-	hoverValid     bool // Is cursor over a valid cell?
-
-	// Pathfinding
-	spawn, base Point   // Start and end points
-	path        []Point // Current path from spawn to base
-	pathBlocked bool    // True if no valid path exists
-
-	// Enemies
-	enemies    []*Enemy
-	spawnTimer int // Ticks until next spawn
-
-	// Towers
-	towers []*Tower
-	lasers []*Laser // Visual effects for shots
-
-	// Game state
-	state     GameState
-	resources int
-
-	// Wave system
-	currentWave     int  // Current wave number (1-indexed)
-	enemiesThisWave int  // Enemies remaining to spawn this wave
-	waveDelay       int  // Ticks until next wave starts
-	totalKills      int  // Total enemies killed
+// BossKilled marks a boss's death with bullet time
+func (g *Game) BossKilled() {
+	g.startBulletTime()
 }
 
-// NewGame creates a new game with an initial grid layout
-func NewGame() *Game {
-	g := &Game{}
+// formatClock formats a tick count as elapsed game time, MM:SS
+func formatClock(ticks int) string {
+	secs := ticks / game.TPS
+	return fmt.Sprintf("%02d:%02d", secs/60, secs%60)
+}
 
-	// Fill with ground
-	for y := 0; y < GridHeight; y++ {
-		for x := 0; x < GridWidth; x++ {
-			g.grid[y][x] = TileGround
-		}
+// Update handles game logic
+func (g *Game) Update() error {
+	// Closing the window (or choosing Quit) saves the session before exiting
+	if ebiten.IsWindowBeingClosed() || g.quitting {
+		g.saveSession()
+		return ebiten.Termination
 	}
 
-	// Add some walls around edges
-	for x := 0; x < GridWidth; x++ {
-		g.grid[0][x] = TileWall
-		g.grid[GridHeight-1][x] = TileWall
-	}
-	for y := 0; y < GridHeight; y++ {
-		g.grid[y][0] = TileWall
-		g.grid[y][GridWidth-1] = TileWall
+	g.handleSettingsInput()
+	if g.MessageTTL > 0 {
+		g.MessageTTL--
 	}
 
-	// Place base (what we're defending) - bottom center
-	g.base = Point{X: GridWidth / 2, Y: GridHeight - 2}
-	g.grid[g.base.Y][g.base.X] = TileBase
+	// An open menu freezes the game and takes all input
+	if g.paused() {
+		g.updateMenu()
+		return nil
+	}
 
-	// Place spawn point - top center
-	g.spawn = Point{X: GridWidth / 2, Y: 1}
-	g.grid[g.spawn.Y][g.spawn.X] = TileSpawn
+	// Hotkeys for on/off options
+	g.handleToggleInput()
 
-	// Add some interior walls for interest
-	for y := 3; y < 8; y++ {
-		g.grid[y][5] = TileWall
-		g.grid[y][14] = TileWall
+	// Number keys pick which tower to build
+	for i := range min(len(game.TowerTypes), len(buildKeys)) {
+		if inpututil.IsKeyJustPressed(buildKeys[i]) {
+			g.selectBuildKind(game.TowerKind(i))
+		}
 	}
 
-	// Calculate initial path
-	g.recalculatePath()
-
-	// Initialize game state
-	g.state = StatePlaying
-	g.resources = StartingResource
-	g.currentWave = 1
-	g.enemiesThisWave = EnemiesPerWave
-	g.waveDelay = 300 // 5 seconds to place initial towers
+	// F12 saves a picture of the board
+	g.handleSnapshotInput()
 
-	return g
-}
+	// F2 saves the game, F4 loads it
+	g.handleSaveInput()
 
-// isWalkable returns true if a tile can be walked through
-func (g *Game) isWalkable(x, y int) bool {
-	if x < 0 || x >= GridWidth || y < 0 || y >= GridHeight {
-		return false
+	// W cycles how many upcoming waves the preview shows
+	if inpututil.IsKeyJustPressed(ebiten.KeyW) {
+		g.lookahead = g.lookahead%game.MaxLookahead + 1
+		g.ShowMessage("Wave preview: next %d", g.lookahead)
 	}
-	tile := g.grid[y][x]
-	return tile == TileGround || tile == TileSpawn || tile == TileBase
-}
 
-// heuristic calculates Manhattan distance
-func heuristic(a, b Point) int {
-	dx := a.X - b.X
-	dy := a.Y - b.Y
-	if dx < 0 {
-		dx = -dx
-	}
-	if dy < 0 {
-		dy = -dy
+	// Space pauses the simulation; Esc with nothing selected opens the menu
+	g.handlePauseInput()
+	if g.paused() {
+		return nil
 	}
-	return dx + dy
-}
 
-// findPath uses A* to find path from start to goal
-func (g *Game) findPath(start, goal Point) []Point {
-	openSet := &priorityQueue{}
-	heap.Init(openSet)
-	heap.Push(openSet, &pqItem{point: start, priority: 0})
+	// D starts today's daily challenge, M a random map, T the tutorial
+	// (from the setup phase or end screen)
+	if g.State != game.StatePlaying || g.InSetupPhase() {
+		if inpututil.IsKeyJustPressed(ebiten.KeyD) {
+			g.replaceWith(game.NewDailyChallenge(time.Now()))
+			return nil
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyM) {
+			g.replaceWith(game.NewGeneratedGame(time.Now().UnixNano()))
+			return nil
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+			g.replaceWith(game.NewTutorialGame())
+			return nil
+		}
+	}
 
-	cameFrom := make(map[Point]Point)
-	gScore := make(map[Point]int)
-	gScore[start] = 0
+	// Handle restart on R key when game is over
+	if g.State != game.StatePlaying {
+		if ebiten.IsKeyPressed(ebiten.KeyR) {
+			g.restart()
+		}
+		return nil
+	}
 
-	// 4-directional movement
-	dirs := []Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+	g.handleSpeedInput()
 
-	for openSet.Len() > 0 {
-		current := heap.Pop(openSet).(*pqItem).point
+	// Get mouse (or touch) position and convert to world, then grid, coordinates
+	tap, longPress := g.updateTouch()
+	sx, sy := g.pointerPosition(tap)
+	g.handleCameraInput(sx, sy, g.handleFacingInput())
+	rightClick := g.handlePanDrag(sx, sy)
+	onContextMenu := g.handleContextMenuInput(sx, sy)
+	onBar := g.handleBuildBarInput(sx, sy, tap)
+	onMinimap := g.handleMinimapInput(sx, sy)
+	wx, wy := g.camera.toWorld(sx, sy)
+	mx, my := int(wx), int(wy)
+	gx, gy := mx/game.CellSize, my/game.CellSize
 
-		if current == goal {
-			// Reconstruct path
-			path := []Point{current}
-			for current != start {
-				current = cameFrom[current]
-				path = append([]Point{current}, path...)
-			}
-			return path
-		}
-
-		for _, d := range dirs {
-			neighbor := Point{X: current.X + d.X, Y: current.Y + d.Y}
+	// Check if cursor is within grid bounds
+	g.hoverValid = !onBar && !onMinimap && !onContextMenu && g.InGrid(gx, gy)
+	if g.hoverValid {
+		g.hoverX, g.hoverY = gx, gy
+	}
 
-			if !g.isWalkable(neighbor.X, neighbor.Y) {
-				continue
-			}
+	if g.editing {
+		g.handleEditInput(rightClick)
+		g.refreshOverlays()
+		return nil
+	}
 
-			tentativeG := gScore[current] + 1
+	g.handleGroupInput()
+	g.handleUpgradeInput()
+	g.handleTargetModeInput()
+	selectingClick := onContextMenu || g.handleMoveInput() || g.handleKillZoneInput(wx, wy) || (g.hoverValid && g.handlePickupInput(wx, wy, tap)) || g.handleManualFireInput() || g.handleSelectionInput(mx, my)
 
-			if oldG, exists := gScore[neighbor]; !exists || tentativeG < oldG {
-				cameFrom[neighbor] = current
-				gScore[neighbor] = tentativeG
-				f := tentativeG + heuristic(neighbor, goal)
-				heap.Push(openSet, &pqItem{point: neighbor, priority: f})
-			}
+	// Right click on a tower opens its actions
+	if g.hoverValid && rightClick && g.Grid[g.hoverY][g.hoverX] == game.TileTower {
+		if t := g.TowerAt(g.hoverX, g.hoverY); t != nil {
+			g.openContextMenu(t, sx, sy)
 		}
 	}
 
-	// No path found
+	// Advance the simulation, handing it the board clicks: left click or tap
+	// builds, right click or long press cancels a queued placement, and long
+	// press sells a tower outright (back to ground). Bullet time runs fewer
+	// steps per frame, fast-forward more.
+	g.syncSettings()
+	g.runSteps(g.stepsThisFrame(), game.Input{
+		Cell:    game.Point{X: g.hoverX, Y: g.hoverY},
+		OnBoard: g.hoverValid,
+		Build:   (ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) && !selectingClick) || tap,
+		Sell:    longPress && !rightClick,
+		Cancel:  rightClick || longPress,
+		Kind:    g.selectedKind,
+	})
+	g.refreshOverlays()
 	return nil
 }
 
-// recalculatePath updates the global path from spawn to base
-func (g *Game) recalculatePath() {
-	g.path = g.findPath(g.spawn, g.base)
-	g.pathBlocked = g.path == nil
+// refreshOverlays recomputes the coverage gaps and flow field once the
+// board has changed under them
+func (g *Game) refreshOverlays() {
+	if g.CoverageDirty {
+		g.GapCells = g.UncoveredPathCells()
+		g.CoverageDirty = false
+		g.placement = PlacementScore{}
+	}
+	if g.showFlow && g.Flow == nil {
+		g.Flow = g.FlowField()
+	}
 }
 
-// recalculateEnemyPaths updates paths for all existing enemies from their current position
-func (g *Game) recalculateEnemyPaths() {
-	for _, e := range g.enemies {
-		// Get enemy's current grid cell
-		gridX := int(e.X) / CellSize
-		gridY := int(e.Y) / CellSize
-		currentCell := Point{X: gridX, Y: gridY}
-
-		// Find new path from current position to base
-		newPath := g.findPath(currentCell, g.base)
-		if newPath != nil {
-			e.Path = newPath
-			e.PathIndex = 1 // Start moving toward second waypoint
-		}
-		// If no path, enemy keeps current path (will walk through obstacle)
-		// This matches the "enemies can break through" design
+// Draw renders the board through the camera, then the UI on top
+func (g *Game) Draw(screen *ebiten.Image) {
+	if g.world == nil {
+		g.world = ebiten.NewImage(g.WorldWidth(), g.WorldHeight())
 	}
+	g.world.Clear()
+	g.drawWorld(g.world)
+	screen.DrawImage(g.world, &ebiten.DrawImageOptions{GeoM: g.camera.geoM()})
+	g.drawUI(screen)
 }
 
-// spawnEnemy creates a new enemy at the spawn point
-func (g *Game) spawnEnemy() {
-	if g.pathBlocked || len(g.path) == 0 {
-		return
-	}
-	// Copy the current path for this enemy
-	pathCopy := make([]Point, len(g.path))
-	copy(pathCopy, g.path)
+// drawTiles fills each cell with its tile color (towers in their kind's
+// color). Void cells are left as background.
+func (g *Game) drawTiles(screen *ebiten.Image) {
+	pal := g.palette()
+	for y := range g.Grid {
+		for x := range g.Grid[y] {
+			tile := g.Grid[y][x]
+			if tile == game.TileVoid {
+				continue
+			}
+			c := pal.Tiles[tile]
+			if tile == game.TileTower {
+				if t := g.TowerAt(x, y); t != nil {
+					c = game.TowerTypes[t.Kind].Color
+				}
+			}
 
-	e := &Enemy{
-		X:         float64(g.spawn.X*CellSize) + CellSize/2,
-		Y:         float64(g.spawn.Y*CellSize) + CellSize/2,
-		PathIndex: 1, // Start moving toward second waypoint (first is spawn)
-		Path:      pathCopy,
-		HP:        EnemyMaxHP,
+			px := float32(x * game.CellSize)
+			py := float32(y * game.CellSize)
+			vector.DrawFilledRect(screen, px, py, game.CellSize, game.CellSize, c, false)
+		}
 	}
-	g.enemies = append(g.enemies, e)
 }
 
-// addTower places a tower and tracks it (returns false if can't afford)
-func (g *Game) addTower(x, y int) bool {
-	if g.resources < TowerCost {
-		return false
+// drawGridLines outlines every cell on the board. Each line is drawn in runs
+// along the edges that border at least one board cell, so none cross void.
+func (g *Game) drawGridLines(screen *ebiten.Image) {
+	pal := g.palette()
+	for x := 0; x <= g.GridWidth(); x++ {
+		px := float32(x * game.CellSize)
+		for _, r := range edgeRuns(g.GridHeight(), func(y int) bool { return g.OnBoard(x-1, y) || g.OnBoard(x, y) }) {
+			vector.StrokeLine(screen, px, float32(r[0]*game.CellSize), px, float32(r[1]*game.CellSize), 1, pal.GridLine, false)
+		}
+	}
+	for y := 0; y <= g.GridHeight(); y++ {
+		py := float32(y * game.CellSize)
+		for _, r := range edgeRuns(g.GridWidth(), func(x int) bool { return g.OnBoard(x, y-1) || g.OnBoard(x, y) }) {
+			vector.StrokeLine(screen, float32(r[0]*game.CellSize), py, float32(r[1]*game.CellSize), py, 1, pal.GridLine, false)
+		}
 	}
-	g.resources -= TowerCost
-	g.grid[y][x] = TileTower
-	g.towers = append(g.towers, &Tower{X: x, Y: y, Cooldown: 0})
-	return true
 }
 
-// removeTower removes a tower (refunds half cost)
-func (g *Game) removeTower(x, y int) {
-	g.grid[y][x] = TileGround
-	g.resources += TowerCost / 2 // Refund half
-	// Remove from tower list
-	for i, t := range g.towers {
-		if t.X == x && t.Y == y {
-			g.towers = append(g.towers[:i], g.towers[i+1:]...)
-			break
+// drawPath marks each lane's route, tinted per lane, or its spawn if it has
+// no route
+func (g *Game) drawPath(screen *ebiten.Image) {
+	pal := g.palette()
+	for i, path := range g.Paths {
+		if path == nil {
+			px := float32(g.Spawns[i].X * game.CellSize)
+			py := float32(g.Spawns[i].Y * game.CellSize)
+			vector.DrawFilledRect(screen, px, py, game.CellSize, game.CellSize, pal.NoPath, false)
+			continue
+		}
+		c := laneTint(pal.Path, i)
+		for _, p := range path {
+			px := float32(p.X*game.CellSize) + game.CellSize/4
+			py := float32(p.Y*game.CellSize) + game.CellSize/4
+			vector.DrawFilledRect(screen, px, py, game.CellSize/2, game.CellSize/2, c, false)
 		}
 	}
 }
 
-// updateEnemies moves all enemies along the path
-func (g *Game) updateEnemies() {
-	alive := make([]*Enemy, 0, len(g.enemies))
+// drawWorld renders the board at full size, in world pixels
+func (g *Game) drawWorld(screen *ebiten.Image) {
+	pal := g.palette()
 
-	for _, e := range g.enemies {
-		// Remove dead enemies and grant reward
-		if e.HP <= 0 {
-			g.resources += KillReward
-			g.totalKills++
-			continue
-		}
+	// Layer 1: Tiles
+	g.drawTiles(screen)
 
-		if e.PathIndex >= len(e.Path) {
-			// Enemy reached the base - GAME OVER
-			g.state = StateLost
-			continue
+	// Mega wave announcement: pulse the board red
+	if g.MegaFlash > 0 {
+		pulse := 0.5 + 0.5*math.Sin(float64(g.MegaFlash)*0.2)
+		flash := color.RGBA{R: 255, A: uint8(60 * pulse)}
+		vector.DrawFilledRect(screen, 0, 0, float32(g.WorldWidth()), float32(g.WorldHeight()), flash, false)
+	}
+
+	// Layer 2: Grid lines
+	g.drawGridLines(screen)
+
+	// Debug: cells A* expanded while finding the path
+	if g.ShowDebug {
+		for _, p := range g.Explored {
+			px := float32(p.X * game.CellSize)
+			py := float32(p.Y * game.CellSize)
+			vector.DrawFilledRect(screen, px, py, game.CellSize, game.CellSize, exploredColor, false)
 		}
+	}
 
-		// Get target waypoint center (from enemy's own path)
-		target := e.Path[e.PathIndex]
-		targetX := float64(target.X*CellSize) + CellSize/2
-		targetY := float64(target.Y*CellSize) + CellSize/2
+	// Tar pits' slowed cells
+	g.drawTar(screen)
 
-		// Calculate direction
-		dx := targetX - e.X
-		dy := targetY - e.Y
-		dist := math.Sqrt(dx*dx + dy*dy)
+	// Enemy close to the base
+	g.drawBaseAlert(screen)
 
-		if dist < EnemySpeed {
-			// Reached waypoint, move to next
-			e.X = targetX
-			e.Y = targetY
-			e.PathIndex++
-		} else {
-			// Move toward waypoint
-			e.X += (dx / dist) * EnemySpeed
-			e.Y += (dy / dist) * EnemySpeed
+	// Incoming enemy warning on the spawn tile
+	g.drawSpawnTelegraph(screen)
+
+	// Layer 3: Path indicator
+	g.drawPath(screen)
+	g.drawFlowField(screen)
+
+	// Layer 3b: Path cells no tower covers
+	if g.showGaps && !g.PathBlocked {
+		for _, p := range g.GapCells {
+			px := float32(p.X * game.CellSize)
+			py := float32(p.Y * game.CellSize)
+			vector.DrawFilledRect(screen, px, py, game.CellSize, game.CellSize, gapColor, false)
 		}
+	}
 
-		alive = append(alive, e)
+	// Layer 4: Hover highlight
+	if g.hoverValid {
+		px := float32(g.hoverX * game.CellSize)
+		py := float32(g.hoverY * game.CellSize)
+		vector.DrawFilledRect(screen, px, py, game.CellSize, game.CellSize, highlightColor, false)
+
+		// Range of the hovered tower, of a tower placed here, or of the
+		// tower being moved here
+		tile := g.Grid[g.hoverY][g.hoverX]
+		switch {
+		case g.moving != nil:
+			g.drawMovePreview(screen)
+		case tile == game.TileTower:
+			if t := g.TowerAt(g.hoverX, g.hoverY); t != nil {
+				g.drawRange(screen, g.hoverX, g.hoverY, g.EffectiveStats(t).Range)
+			}
+		case tile == game.TileGround:
+			g.drawRange(screen, g.hoverX, g.hoverY, game.TowerTypes[g.selectedKind].Range)
+			g.drawPlacementScore(screen, g.hoverX, g.hoverY)
+		}
 	}
 
-	g.enemies = alive
-}
+	// Placements waiting in the build queue
+	g.drawBuildQueue(screen)
 
-// updateTowers handles tower targeting and shooting
-func (g *Game) updateTowers() {
-	for _, t := range g.towers {
-		// Decrease cooldown
-		if t.Cooldown > 0 {
-			t.Cooldown--
-			continue
-		}
+	// Armed traps
+	g.drawTraps(screen)
 
-		// Find target: enemy in range that is furthest along its path (closest to base)
-		towerX := float64(t.X*CellSize) + CellSize/2
-		towerY := float64(t.Y*CellSize) + CellSize/2
+	// Facing of directional towers
+	g.drawFacings(screen)
 
-		var target *Enemy
-		bestProgress := -1
+	// Ready bounce pads
+	g.drawBouncePads(screen)
 
-		for _, e := range g.enemies {
-			if e.HP <= 0 {
-				continue
-			}
+	// Manual towers' ready lights
+	g.drawManualFire(screen)
 
-			// Check range
-			dx := e.X - towerX
-			dy := e.Y - towerY
-			dist := math.Sqrt(dx*dx + dy*dy)
+	// Shots left on towers that get used up
+	g.drawShotPips(screen)
 
-			if dist > TowerRange {
-				continue
-			}
+	// Upgrade levels
+	g.drawLevels(screen)
 
-			// "First in path" = highest PathIndex (closest to base)
-			if e.PathIndex > bestProgress {
-				bestProgress = e.PathIndex
-				target = e
-			}
-		}
+	// Targeting modes
+	g.drawTargetModes(screen)
 
-		// Fire at target
-		if target != nil {
-			target.HP -= TowerDamage
-			t.Cooldown = TowerCooldown
+	// Towers still being built
+	g.drawConstruction(screen)
 
-			// Create laser visual
-			g.lasers = append(g.lasers, &Laser{
-				FromX: towerX,
-				FromY: towerY,
-				ToX:   target.X,
-				ToY:   target.Y,
-				TTL:   LaserDuration,
-			})
-		}
-	}
-}
+	// Leveling towers' kill stacks
+	g.drawKillStacks(screen)
 
-// updateLasers decrements laser TTL and removes expired ones
-func (g *Game) updateLasers() {
-	alive := make([]*Laser, 0, len(g.lasers))
-	for _, l := range g.lasers {
-		l.TTL--
-		if l.TTL > 0 {
-			alive = append(alive, l)
-		}
-	}
-	g.lasers = alive
-}
+	// Selected towers and selection box
+	g.drawSelection(screen)
 
-// Update handles game logic
-func (g *Game) Update() error {
-	// Handle restart on R key when game is over
-	if g.state != StatePlaying {
-		if ebiten.IsKeyPressed(ebiten.KeyR) {
-			*g = *NewGame()
-		}
-		return nil
-	}
+	// Tower health and enemy attacks in siege mode
+	g.drawSiege(screen)
+
+	// Damaged bases' health
+	g.drawBaseHP(screen)
 
-	// Wave spawning logic
-	if g.waveDelay > 0 {
-		g.waveDelay--
-	} else if g.enemiesThisWave > 0 {
-		// Spawn enemies for current wave
-		g.spawnTimer--
-		if g.spawnTimer <= 0 {
-			g.spawnEnemy()
-			g.enemiesThisWave--
-			g.spawnTimer = SpawnInterval
-		}
-	} else if len(g.enemies) == 0 {
-		// Wave complete, all enemies dead
-		if g.currentWave >= TotalWaves {
-			// All waves complete - WIN!
-			g.state = StateWon
+	// Links between enemies guarding each other in formation
+	g.drawFormations(screen)
+
+	// Healer auras and the allies they're healing
+	g.drawHealing(screen)
+
+	// Layer 5: Enemies with HP bars
+	rage := g.Rage()
+	for _, e := range g.Enemies {
+		if e.Burrowed > 0 {
+			drawMound(screen, e)
+			continue
+		}
+		if e.Flies() {
+			drawFlyer(screen, e, chillTint(e), pal.EnemyOutline)
 		} else {
-			// Start next wave
-			g.currentWave++
-			g.enemiesThisWave = EnemiesPerWave + g.currentWave // More enemies each wave
-			g.waveDelay = WaveDelay
+			vector.DrawFilledCircle(screen, float32(e.X), float32(e.Y), float32(e.Radius()), chillTint(e), true)
+		}
+		drawShred(screen, e)
+		drawRage(screen, e, rage)
+		drawStun(screen, e)
+		g.drawStreaks(screen, e)
+		if pal.EnemyOutline.A > 0 && !e.Flies() {
+			vector.StrokeCircle(screen, float32(e.X), float32(e.Y), float32(e.Radius()), 2, pal.EnemyOutline, true)
 		}
-	}
 
-	// Move enemies
-	g.updateEnemies()
+		// HP bar
+		hpRatio := min(e.HP/game.MaxHP(e.Kind), 1)
+		barWidth := float32(e.Radius() * 2)
+		barHeight := float32(4)
+		barX := float32(e.X) - barWidth/2
+		barY := float32(e.Y-e.Radius()) - 6
 
-	// Tower targeting and shooting
-	g.updateTowers()
+		vector.DrawFilledRect(screen, barX, barY, barWidth, barHeight, color.RGBA{60, 60, 60, 255}, false)
+		hpColor := color.RGBA{uint8(255 * (1 - hpRatio)), uint8(255 * hpRatio), 0, 255}
+		vector.DrawFilledRect(screen, barX, barY, barWidth*float32(hpRatio), barHeight, hpColor, false)
 
-	// Update laser visuals
-	g.updateLasers()
+		// Shield bar above the HP bar
+		if e.ShieldMax > 0 {
+			shieldY := barY - barHeight - 1
+			vector.DrawFilledRect(screen, barX, shieldY, barWidth, barHeight, color.RGBA{60, 60, 60, 255}, false)
+			vector.DrawFilledRect(screen, barX, shieldY, barWidth*float32(e.Shield/e.ShieldMax), barHeight, shieldColor, false)
+		}
+	}
 
-	// Get mouse position and convert to grid coordinates
-	mx, my := ebiten.CursorPosition()
-	gx, gy := mx/CellSize, my/CellSize
+	// Rings showing how close each enemy is to the base
+	g.drawDangerRings(screen)
 
-	// Check if cursor is within grid bounds
-	g.hoverValid = gx >= 0 && gx < GridWidth && gy >= 0 && gy < GridHeight
-	if g.hoverValid {
-		g.hoverX, g.hoverY = gx, gy
+	// Resource orbs waiting to be collected
+	g.drawPickups(screen)
+
+	// Player-called kill zone
+	g.drawKillZone(screen)
+
+	// Layer 6: Lasers (topmost)
+	for _, l := range g.Lasers {
+		c := l.Color
+		if c.A == 0 {
+			c = pal.Laser
+		}
+		drawLaser(screen, l, c)
 	}
+	g.drawExplosions(screen)
+	g.drawProjectiles(screen)
 
-	// Handle clicks (only when playing)
-	if g.hoverValid && g.state == StatePlaying {
-		tile := g.grid[g.hoverY][g.hoverX]
-		gridChanged := false
+	// Floating texts (over the board, under the status bar)
+	for _, f := range g.FloatTexts {
+		g.drawText(screen, f.Text, int(f.X)-g.textWidth(f.Text)/2, int(f.Y)-g.lineHeight()/2)
+	}
+}
 
-		// Left click: place tower (only on ground, if can afford)
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-			if tile == TileGround {
-				if g.addTower(g.hoverX, g.hoverY) {
-					gridChanged = true
+// drawUI renders everything fixed to the screen rather than the board
+func (g *Game) drawUI(screen *ebiten.Image) {
+	// Stats tooltip for the hovered tower, or for the tower that would be built
+	if g.hoverValid {
+		switch g.Grid[g.hoverY][g.hoverX] {
+		case game.TileTower:
+			if t := g.TowerAt(g.hoverX, g.hoverY); t != nil {
+				lines := append(statsLines(g.EffectiveStats(t)), fmt.Sprintf("Sell: +%d", g.SellValue(t)))
+				if t.Level < game.MaxTowerLevel {
+					lines = append(lines, fmt.Sprintf("Upgrade (;): %d", game.UpgradeCost(t)))
 				}
+				g.drawTooltip(screen, lines)
 			}
+		case game.TileGround:
+			tt := game.TowerTypes[g.selectedKind]
+			lines := append([]string{fmt.Sprintf("Build %s: %d", tt.Name, tt.Cost)}, statsLines(tt.TowerStats)...)
+			g.drawTooltip(screen, lines)
 		}
+	}
 
-		// Right click: remove tower (back to ground)
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
-			if tile == TileTower {
-				g.removeTower(g.hoverX, g.hoverY)
-				gridChanged = true
-			}
-		}
+	g.drawBuildBar(screen)
+	g.drawMinimap(screen)
 
-		// Recalculate paths if grid changed
-		if gridChanged {
-			g.recalculatePath()
-			g.recalculateEnemyPaths()
+	// Layer 7: UI Text
+	var statusText string
+	switch g.State {
+	case game.StatePlaying:
+		waveStatus := fmt.Sprintf("Wave %d/%d", g.CurrentWave, game.TotalWaves)
+		if g.Endless {
+			waveStatus = fmt.Sprintf("Wave %d (endless)", g.CurrentWave)
 		}
+		if g.MegaWave {
+			waveStatus = "MEGA WAVE! " + waveStatus
+		}
+		if g.IsBossWave(g.CurrentWave) {
+			waveStatus = "BOSS WAVE! " + waveStatus
+		}
+		if g.gameSpeed() > 1 {
+			waveStatus += fmt.Sprintf(" [%dx]", g.gameSpeed())
+		}
+		if g.WaveDelay > 0 {
+			waveStatus += fmt.Sprintf(" (next in %ds)", g.WaveDelay/game.TPS+1)
+		}
+		build := game.TowerTypes[g.selectedKind]
+		statusText = fmt.Sprintf("%s | %s | Resources: %d | Kills: %d | Build: %s (%d) | Path: %d (best %d)",
+			waveStatus, formatClock(g.Tick), g.Resources, g.TotalKills, build.Name, build.Cost, g.PathLength(), g.LongestPath)
+		if g.SurgeEnabled {
+			statusText += fmt.Sprintf(" | Surges: %d/%d", g.WaveSurges, game.SurgeMaxPerWave)
+		}
+		if g.MaxTowers > 0 {
+			statusText += fmt.Sprintf(" | Towers: %d/%d", len(g.Towers), g.MaxTowers)
+		}
+		if g.ZoneCooldown > 0 {
+			statusText += fmt.Sprintf(" | Kill zone in %ds", g.ZoneCooldown/game.TPS+1)
+		} else {
+			statusText += " | Kill zone ready (`)"
+		}
+	case game.StateWon:
+		statusText = fmt.Sprintf("YOU WIN! Survived all %d waves! Kills: %d | Score: %d | Press R to restart",
+			game.TotalWaves, g.TotalKills, g.Score())
+	case game.StateLost:
+		reason := "Enemy reached base!"
+		if g.Surrendered {
+			reason = "You surrendered."
+		}
+		statusText = fmt.Sprintf("GAME OVER - %s Wave %d | Kills: %d | Score: %d | Press R to restart",
+			reason, g.CurrentWave, g.TotalKills, g.Score())
 	}
-
-	return nil
-}
-
-// Draw renders the game
-func (g *Game) Draw(screen *ebiten.Image) {
-	// Layer 1: Tiles
-	for y := 0; y < GridHeight; y++ {
-		for x := 0; x < GridWidth; x++ {
-			tile := g.grid[y][x]
-			c := tileColors[tile]
-
-			px := float32(x * CellSize)
-			py := float32(y * CellSize)
-			vector.DrawFilledRect(screen, px, py, CellSize, CellSize, c, false)
+	if g.State != game.StatePlaying {
+		statusText += fmt.Sprintf(" | Time: %s | Overkill dealt: %.0f", formatClock(g.Tick), g.TotalOverkill)
+	}
+	if g.Daily {
+		statusText = fmt.Sprintf("Daily %s | ", g.DailyDate.Format(time.DateOnly)) + statusText
+		if g.State != game.StatePlaying {
+			statusText += fmt.Sprintf(" | Today's best: %d", max(g.DailyBest, g.Score()))
 		}
 	}
-
-	// Layer 2: Grid lines
-	for x := 0; x <= GridWidth; x++ {
-		px := float32(x * CellSize)
-		vector.StrokeLine(screen, px, 0, px, ScreenHeight, 1, gridLineColor, false)
+	if g.editing {
+		statusText = "EDITING MAP (F11 exits, F2 saves): left wall, right ground, Shift+click spawn, Ctrl+click base | " + statusText
 	}
-	for y := 0; y <= GridHeight; y++ {
-		py := float32(y * CellSize)
-		vector.StrokeLine(screen, 0, py, ScreenWidth, py, 1, gridLineColor, false)
+	if g.frozen {
+		statusText = fmt.Sprintf("PAUSED at tick %d (Space resumes, . steps) | ", g.Tick) + statusText
 	}
-
-	// Layer 3: Path indicator
-	if g.pathBlocked {
-		px := float32(g.spawn.X * CellSize)
-		py := float32(g.spawn.Y * CellSize)
-		vector.DrawFilledRect(screen, px, py, CellSize, CellSize, noPathColor, false)
-	} else {
-		for _, p := range g.path {
-			px := float32(p.X*CellSize) + CellSize/4
-			py := float32(p.Y*CellSize) + CellSize/4
-			vector.DrawFilledRect(screen, px, py, CellSize/2, CellSize/2, pathColor, false)
+	if g.PracticeWave > 0 {
+		statusText = fmt.Sprintf("Practice from wave %d (unscored) | ", g.PracticeWave) + statusText
+	}
+	y := g.drawStatus(screen, statusText)
+	if g.State == game.StatePlaying {
+		y = g.drawWaveProgress(screen, y)
+	}
+	if g.MessageTTL > 0 {
+		g.drawText(screen, g.Message, 0, y)
+	}
+	if g.State == game.StatePlaying {
+		top := y
+		if g.WaveDelay == 0 {
+			top = g.drawEnemyCounts(screen, y)
+		} else {
+			top = g.drawWaveReport(screen, y)
 		}
+		top = g.drawWaveHealth(screen, top)
+		g.drawWavePreview(screen, top)
 	}
-
-	// Layer 4: Hover highlight
-	if g.hoverValid {
-		px := float32(g.hoverX * CellSize)
-		py := float32(g.hoverY * CellSize)
-		vector.DrawFilledRect(screen, px, py, CellSize, CellSize, highlightColor, false)
+	if g.ShowDebug {
+		debug := fmt.Sprintf("A* expanded %d cells | State %016x", len(g.Explored), g.StateDigest())
+		if g.stepsDeferred > 0 {
+			debug += fmt.Sprintf(" | Over frame budget, %d steps deferred", g.stepsDeferred)
+		}
+		g.drawText(screen, debug, 0, g.screenHeight()-g.buildBarHeight()-g.lineHeight())
 	}
 
-	// Layer 5: Enemies with HP bars
-	for _, e := range g.enemies {
-		vector.DrawFilledCircle(screen, float32(e.X), float32(e.Y), EnemyRadius, enemyColor, true)
+	g.drawSpawnStall(screen)
+	g.drawTowerTooltip(screen)
+	g.drawContextMenu(screen)
 
-		// HP bar
-		hpRatio := e.HP / EnemyMaxHP
-		barWidth := float32(EnemyRadius * 2)
-		barHeight := float32(4)
-		barX := float32(e.X) - barWidth/2
-		barY := float32(e.Y) - EnemyRadius - 6
+	// Open menu over everything
+	g.drawMenu(screen)
+}
 
-		vector.DrawFilledRect(screen, barX, barY, barWidth, barHeight, color.RGBA{60, 60, 60, 255}, false)
-		hpColor := color.RGBA{uint8(255 * (1 - hpRatio)), uint8(255 * hpRatio), 0, 255}
-		vector.DrawFilledRect(screen, barX, barY, barWidth*float32(hpRatio), barHeight, hpColor, false)
+// drawRange shows the area a tower at (x, y) covers: a ring normally, or the
+// actually visible cells when walls block line of sight
+func (g *Game) drawRange(screen *ebiten.Image, x, y int, rng float64) {
+	if !g.RequireLineOfSight {
+		cx, cy := game.CellCenter(x, y)
+		vector.StrokeCircle(screen, float32(cx), float32(cy), float32(rng), 1, rangeColor, true)
+		return
 	}
-
-	// Layer 6: Lasers (topmost)
-	for _, l := range g.lasers {
-		vector.StrokeLine(screen, float32(l.FromX), float32(l.FromY), float32(l.ToX), float32(l.ToY), 2, laserColor, false)
+	for _, c := range g.VisibleCells(x, y, rng) {
+		px := float32(c.X * game.CellSize)
+		py := float32(c.Y * game.CellSize)
+		vector.DrawFilledRect(screen, px, py, game.CellSize, game.CellSize, rangeColor, false)
 	}
-
-	// Layer 7: UI Text
-	var statusText string
-	switch g.state {
-	case StatePlaying:
-		waveStatus := fmt.Sprintf("Wave %d/%d", g.currentWave, TotalWaves)
-		if g.waveDelay > 0 {
-			waveStatus += fmt.Sprintf(" (next in %ds)", g.waveDelay/60+1)
-		}
-		statusText = fmt.Sprintf("%s | Resources: %d | Kills: %d | Tower cost: %d",
-			waveStatus, g.resources, g.totalKills, TowerCost)
-	case StateWon:
-		statusText = fmt.Sprintf("YOU WIN! Survived all %d waves! Kills: %d | Press R to restart", TotalWaves, g.totalKills)
-	case StateLost:
-		statusText = fmt.Sprintf("GAME OVER - Enemy reached base! Wave %d | Kills: %d | Press R to restart",
-			g.currentWave, g.totalKills)
-	}
-	ebitenutil.DebugPrint(screen, statusText)
 }
 
 // Layout returns the game's screen dimensions
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return ScreenWidth, ScreenHeight
+	return g.screenWidth(), g.screenHeight()
 }
 
 func main() {
-	ebiten.SetWindowSize(ScreenWidth, ScreenHeight)
 	ebiten.SetWindowTitle("Claude TD - Demo 0.6")
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetWindowClosingHandled(true)
+
+	if path, err := game.ConfigPath("towers.json"); err == nil {
+		types, err := game.LoadTowerTypes(path)
+		if err != nil {
+			log.Printf("loading custom towers (using built-ins): %v", err)
+		}
+		game.TowerTypes = types
+	}
+
+	settings, err := loadSettings()
+	if err != nil {
+		log.Printf("loading settings: %v", err)
+	}
+	g := &Game{settings: settings, sound: newSoundBoard(1), showMinimap: true}
+	g.sound.setVolume(settings.effectVolume())
+	g.replaceWith(game.NewGame(game.GridWidth, game.GridHeight))
+	ebiten.SetWindowSize(g.screenWidth(), g.screenHeight())
+	g.openMenu(mainMenu())
 
-	game := NewGame()
-	if err := ebiten.RunGame(game); err != nil {
+	if err := ebiten.RunGame(g); err != nil {
 		log.Fatal(err)
 	}
 }
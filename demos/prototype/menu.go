@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var menuDimColor = color.RGBA{R: 0, G: 0, B: 0, A: 160}
+
+var menuPanelColor = color.RGBA{R: 20, G: 20, B: 30, A: 230}
+
+var menuCursorColor = color.RGBA{R: 255, G: 255, B: 255, A: 50}
+
+// MenuItem is one line of a menu
+type MenuItem struct {
+	Label  func(g *Game) string
+	Select func(g *Game)          // Enter; may be nil
+	Adjust func(g *Game, dir int) // Left (-1) / right (+1); may be nil
+}
+
+// Menu is a keyboard-navigated list shown over the paused board
+type Menu struct {
+	Title     string
+	Items     []MenuItem
+	Escapable bool // Esc closes it (the root main menu can't be escaped)
+	cursor    int
+}
+
+// label returns a MenuItem label function for fixed text
+func label(s string) func(*Game) string {
+	return func(*Game) string { return s }
+}
+
+// paused returns true while a menu is open; the game doesn't advance then
+func (g *Game) paused() bool {
+	return len(g.menus) > 0
+}
+
+// openMenu shows a menu on top of any already open
+func (g *Game) openMenu(m *Menu) {
+	g.menus = append(g.menus, m)
+}
+
+// closeMenu returns to the menu underneath, or to the game
+func (g *Game) closeMenu() {
+	g.menus = g.menus[:len(g.menus)-1]
+}
+
+// handlePauseInput pauses or resumes the simulation on Space, leaving the
+// board open for building, and opens the pause menu on Esc when there's no
+// selection, context menu, armed kill zone, or tower move for Esc to clear.
+// S asks to surrender a game in progress.
+func (g *Game) handlePauseInput() {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.frozen = !g.frozen
+		if g.frozen {
+			g.ShowMessage("Paused: Space resumes, . steps one tick")
+		} else {
+			g.ShowMessage("Resumed")
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) && len(g.selected) == 0 && g.contextMenu == nil && !g.zoneArmed && g.moving == nil {
+		g.openMenu(pauseMenu())
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyS) && g.State == game.StatePlaying {
+		g.openMenu(surrenderMenu())
+	}
+}
+
+// updateMenu navigates the top menu: Up/Down move, Enter/Space select,
+// Left/Right adjust, Esc goes back
+func (g *Game) updateMenu() {
+	m := g.menus[len(g.menus)-1]
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		m.cursor = (m.cursor + len(m.Items) - 1) % len(m.Items)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		m.cursor = (m.cursor + 1) % len(m.Items)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		if adjust := m.Items[m.cursor].Adjust; adjust != nil {
+			adjust(g, -1)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight):
+		if adjust := m.Items[m.cursor].Adjust; adjust != nil {
+			adjust(g, 1)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		if sel := m.Items[m.cursor].Select; sel != nil {
+			sel(g)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		if m.Escapable {
+			g.closeMenu()
+		}
+	}
+}
+
+// mainMenu is shown at startup and after quitting a game. Continue leads it
+// while there's a last session to resume.
+func mainMenu() *Menu {
+	start := func(newGame func() *game.Game) func(*Game) {
+		return func(g *Game) { g.replaceWith(newGame()) }
+	}
+	practiceWave := 1
+	var resume []MenuItem
+	if game.HasSession() {
+		resume = append(resume, MenuItem{Label: label("Continue"), Select: (*Game).continueSession})
+	}
+	return &Menu{
+		Title: "Claude TD",
+		Items: append(resume,
+			MenuItem{Label: label("New Game"), Select: start(func() *game.Game { return game.NewGame(game.GridWidth, game.GridHeight) })},
+			MenuItem{Label: label("Endless"), Select: start(func() *game.Game {
+				ng := game.NewGame(game.GridWidth, game.GridHeight)
+				ng.Endless = true
+				return ng
+			})},
+			MenuItem{Label: label("Daily Challenge"), Select: start(func() *game.Game { return game.NewDailyChallenge(time.Now()) })},
+			MenuItem{Label: label("Random Map"), Select: start(func() *game.Game { return game.NewGeneratedGame(time.Now().UnixNano()) })},
+			MenuItem{Label: label("Tutorial"), Select: start(game.NewTutorialGame)},
+			MenuItem{Label: label("Two Lanes"), Select: start(game.NewTwoLaneGame)},
+			MenuItem{Label: label("Custom Map"), Select: start(func() *game.Game { return game.NewNamedGame(game.CustomMapName) })},
+			MenuItem{
+				Label:  func(*Game) string { return fmt.Sprintf("Practice wave: < %d >", practiceWave) },
+				Adjust: func(_ *Game, dir int) { practiceWave = (practiceWave+dir+game.TotalWaves-1)%game.TotalWaves + 1 },
+				Select: func(g *Game) { g.replaceWith(game.NewPracticeGame(practiceWave)) },
+			},
+			MenuItem{Label: label("Settings"), Select: func(g *Game) { g.openMenu(settingsMenu()) }},
+			MenuItem{Label: label("Quit"), Select: func(g *Game) { g.quitting = true }},
+		),
+	}
+}
+
+// continueSession resumes the game that was in progress when the window
+// last closed
+func (g *Game) continueSession() {
+	ng, err := game.LoadSession()
+	if err != nil {
+		log.Printf("loading last session: %v", err)
+		g.ShowMessage("Couldn't continue the last session")
+		return
+	}
+	g.replaceWith(ng)
+}
+
+// pauseMenu is opened from a game in progress
+func pauseMenu() *Menu {
+	return &Menu{
+		Title:     "Paused",
+		Escapable: true,
+		Items: []MenuItem{
+			{Label: label("Resume"), Select: (*Game).closeMenu},
+			{Label: label("Restart"), Select: (*Game).restart},
+			{Label: label("Settings"), Select: func(g *Game) { g.openMenu(settingsMenu()) }},
+			{Label: label("Controls"), Select: func(g *Game) { g.openMenu(controlsMenu()) }},
+			{Label: label("Surrender"), Select: func(g *Game) { g.openMenu(surrenderMenu()) }},
+			{Label: label("Quit to Main Menu"), Select: func(g *Game) {
+				g.replaceWith(game.NewGame(game.GridWidth, game.GridHeight))
+				g.openMenu(mainMenu())
+			}},
+		},
+	}
+}
+
+// surrenderMenu confirms giving up. Surrendering closes every menu to show
+// the end screen; No goes back to wherever it was opened from.
+func surrenderMenu() *Menu {
+	return &Menu{
+		Title:     "Surrender this run?",
+		Escapable: true,
+		Items: []MenuItem{
+			{Label: label("No, keep playing"), Select: (*Game).closeMenu},
+			{Label: label("Yes, surrender"), Select: func(g *Game) {
+				g.menus = nil
+				g.Surrender()
+			}},
+		},
+	}
+}
+
+// settingsMenu gathers volume, text size, and every toggle in one place
+func settingsMenu() *Menu {
+	items := []MenuItem{
+		{
+			Label:  func(g *Game) string { return fmt.Sprintf("Master volume: < %.0f%% >", g.settings.MasterVolume*100) },
+			Adjust: func(g *Game, dir int) { g.adjustVolume(float64(dir)*VolumeStep, false) },
+		},
+		{
+			Label:  func(g *Game) string { return fmt.Sprintf("SFX volume: < %.0f%% >", g.settings.SFXVolume*100) },
+			Adjust: func(g *Game, dir int) { g.adjustVolume(float64(dir)*VolumeStep, true) },
+		},
+		{
+			Label:  func(g *Game) string { return fmt.Sprintf("Text size: < %.0f%% >", g.settings.TextScale*100) },
+			Adjust: func(g *Game, dir int) { g.adjustTextScale(float64(dir) * TextScaleStep) },
+		},
+		{
+			Label:  func(g *Game) string { return fmt.Sprintf("Shot tracers: < %d ticks >", g.settings.TracerTicks) },
+			Adjust: func(g *Game, dir int) { g.adjustTracerTicks(dir * TracerStep) },
+		},
+		{
+			Label: func(g *Game) string { return fmt.Sprintf("Enemy routing: < %s >", g.Routing) },
+			Adjust: func(g *Game, dir int) {
+				if dir > 0 {
+					g.SetRouting(g.Routing.Next())
+				} else {
+					g.SetRouting(g.Routing.Prev())
+				}
+			},
+			Select: func(g *Game) { g.SetRouting(g.Routing.Next()) },
+		},
+	}
+	for _, t := range toggles {
+		items = append(items, MenuItem{
+			Label: func(g *Game) string {
+				state := "OFF"
+				if t.Get(g) {
+					state = "ON"
+				}
+				return fmt.Sprintf("%s (%s): %s", t.Name, t.Key, state)
+			},
+			Select: t.flip,
+		})
+	}
+	items = append(items, MenuItem{Label: label("Back"), Select: (*Game).closeMenu})
+	return &Menu{Title: "Settings", Items: items, Escapable: true}
+}
+
+// controls lists the hotkeys that aren't toggles, for the controls menu;
+// the settings menu shows each toggle's
+var controls = []struct{ keys, action string }{
+	{"1-9, 0", "pick a tower kind"},
+	{";", "upgrade the hovered tower"},
+	{"Space", "pause or resume"},
+	{".", "step one tick while paused"},
+	{"Esc", "clear the selection, or open the menu"},
+	{"Delete", "sell the selected towers"},
+	{"F", "switch the selected towers to manual fire"},
+	{"`", "arm a kill zone"},
+	{"Tab, Shift+Tab", "speed up, slow down"},
+	{"W", "preview more waves"},
+	{"S", "surrender"},
+	{"Arrows", "pan the view"},
+	{"+ and -", "master volume (with Shift, SFX volume)"},
+	{"[ and ]", "text size"},
+	{"F2, F4", "save the game, load it (in the map editor F2 saves the map)"},
+	{"F12", "save a board snapshot"},
+	{"D, M, T", "daily challenge, random map, tutorial (before the first wave)"},
+	{"R", "restart once the game is over"},
+}
+
+// controlsMenu lists the hotkeys
+func controlsMenu() *Menu {
+	var items []MenuItem
+	for _, c := range controls {
+		items = append(items, MenuItem{Label: label(fmt.Sprintf("%s: %s", c.keys, c.action))})
+	}
+	items = append(items, MenuItem{Label: label("Back"), Select: (*Game).closeMenu})
+	return &Menu{Title: "Controls", Items: items, Escapable: true}
+}
+
+// drawMenu dims the board and draws the top menu centered on the screen
+func (g *Game) drawMenu(screen *ebiten.Image) {
+	if !g.paused() {
+		return
+	}
+	m := g.menus[len(g.menus)-1]
+	vector.DrawFilledRect(screen, 0, 0, float32(g.screenWidth()), float32(g.screenHeight()), menuDimColor, false)
+
+	lh := g.lineHeight()
+	width := g.textWidth(m.Title)
+	for _, it := range m.Items {
+		width = max(width, g.textWidth(it.Label(g)))
+	}
+	width += 4 * lh
+	height := (len(m.Items) + 2) * lh
+	x := (g.screenWidth() - width) / 2
+	y := (g.screenHeight() - height) / 2
+	vector.DrawFilledRect(screen, float32(x-lh), float32(y-lh), float32(width+2*lh), float32(height+2*lh), menuPanelColor, false)
+
+	g.drawText(screen, m.Title, (g.screenWidth()-g.textWidth(m.Title))/2, y)
+	for i, it := range m.Items {
+		iy := y + (i+2)*lh
+		if i == m.cursor {
+			vector.DrawFilledRect(screen, float32(x), float32(iy), float32(width), float32(lh), menuCursorColor, false)
+		}
+		g.drawText(screen, it.Label(g), x+lh, iy)
+	}
+}
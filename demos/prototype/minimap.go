@@ -0,0 +1,93 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+const (
+	MinimapCell   = 6 // Minimap pixels per grid cell
+	MinimapMargin = 8 // Gap between the minimap and the screen edge
+)
+
+var minimapBorderColor = color.RGBA{R: 255, G: 255, B: 255, A: 200}
+
+// minimapRect returns the minimap's screen position and size (bottom-right
+// corner, above the build bar)
+func (g *Game) minimapRect() (x, y, w, h float32) {
+	w, h = float32(g.GridWidth()*MinimapCell), float32(g.GridHeight()*MinimapCell)
+	bottom := float32(g.screenHeight() - g.buildBarHeight())
+	return float32(g.screenWidth()) - w - MinimapMargin, bottom - h - MinimapMargin, w, h
+}
+
+// minimapVisible returns true if the minimap is shown: it's enabled and the
+// view doesn't already show the whole grid
+func (g *Game) minimapVisible() bool {
+	return g.showMinimap && g.camera.zoomedIn()
+}
+
+// overMinimap returns true if a screen position is on the visible minimap
+func (g *Game) overMinimap(mx, my int) bool {
+	if !g.minimapVisible() {
+		return false
+	}
+	x, y, w, h := g.minimapRect()
+	fx, fy := float32(mx), float32(my)
+	return fx >= x && fx < x+w && fy >= y && fy < y+h
+}
+
+// handleMinimapInput recenters the camera on the clicked minimap position.
+// Returns true if the cursor is over the minimap, so the board ignores it.
+func (g *Game) handleMinimapInput(mx, my int) bool {
+	if !g.overMinimap(mx, my) {
+		return false
+	}
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		x, y, _, _ := g.minimapRect()
+		scale := float64(game.CellSize) / MinimapCell
+		g.camera.centerOn((float64(mx)-float64(x))*scale, (float64(my)-float64(y))*scale)
+	}
+	return true
+}
+
+// drawMinimap draws the whole grid at a small scale with towers, enemies,
+// and the rectangle the main view currently shows
+func (g *Game) drawMinimap(screen *ebiten.Image) {
+	if !g.minimapVisible() {
+		return
+	}
+	pal := g.palette()
+	mx, my, mw, mh := g.minimapRect()
+
+	for y := range g.Grid {
+		for x := range g.Grid[y] {
+			if g.Grid[y][x] == game.TileVoid {
+				continue
+			}
+			c := pal.Tiles[g.Grid[y][x]]
+			if g.Grid[y][x] == game.TileTower {
+				c = pal.Tiles[game.TileGround] // Towers are drawn as dots below
+			}
+			vector.DrawFilledRect(screen, mx+float32(x*MinimapCell), my+float32(y*MinimapCell), MinimapCell, MinimapCell, c, false)
+		}
+	}
+
+	// Scale from world pixels to minimap pixels
+	scale := float32(MinimapCell) / game.CellSize
+	for _, t := range g.Towers {
+		cx, cy := game.CellCenter(t.X, t.Y)
+		vector.DrawFilledCircle(screen, mx+float32(cx)*scale, my+float32(cy)*scale, MinimapCell/2, game.TowerTypes[t.Kind].Color, true)
+	}
+	for _, e := range g.Enemies {
+		vector.DrawFilledCircle(screen, mx+float32(e.X)*scale, my+float32(e.Y)*scale, 1.5, game.EnemyTypes[e.Kind].Color, true)
+	}
+
+	// Viewport
+	vw, vh := g.camera.viewSize()
+	vector.StrokeRect(screen, mx+float32(g.camera.X)*scale, my+float32(g.camera.Y)*scale,
+		float32(vw)*scale, float32(vh)*scale, 1, minimapBorderColor, false)
+	vector.StrokeRect(screen, mx, my, mw, mh, 1, minimapBorderColor, false)
+}
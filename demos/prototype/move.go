@@ -0,0 +1,62 @@
+package main
+
+import (
+	"image/color"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var badMoveColor = color.RGBA{R: 255, G: 40, B: 40, A: GhostAlpha}
+
+// startMove picks up a tower to relocate; the next click on the board puts
+// it down
+func (g *Game) startMove(t *game.Tower) {
+	g.moving = t
+	g.ShowMessage("Moving %s: click a ground cell (Esc cancels)", game.TowerTypes[t.Kind].Name)
+}
+
+// handleMoveInput places the tower being moved on the clicked cell, or
+// cancels on Esc or right click. Returns true while a move is underway, so
+// clicks don't also place or select.
+func (g *Game) handleMoveInput() bool {
+	if g.moving == nil {
+		return false
+	}
+	if !slices.Contains(g.Towers, g.moving) { // Destroyed meanwhile
+		g.moving = nil
+		return false
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		g.moving = nil
+		return true
+	}
+	if g.hoverValid && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if g.MoveTower(g.moving, g.hoverX, g.hoverY) {
+			g.moving = nil
+		} else {
+			g.ShowMessage("Can't move there")
+		}
+	}
+	return true
+}
+
+// drawMovePreview draws the tower being moved as a ghost on the hovered
+// cell, red if it can't go there, with the range it would cover
+func (g *Game) drawMovePreview(screen *ebiten.Image) {
+	t := g.moving
+	if t == nil || !g.hoverValid {
+		return
+	}
+	c := game.TowerTypes[t.Kind].Color
+	c.A = GhostAlpha
+	if !g.CanMoveTo(t, g.hoverX, g.hoverY) {
+		c = badMoveColor
+	}
+	px, py := float32(g.hoverX*game.CellSize), float32(g.hoverY*game.CellSize)
+	vector.DrawFilledRect(screen, px, py, game.CellSize, game.CellSize, c, false)
+	g.drawRange(screen, g.hoverX, g.hoverY, g.EffectiveStats(t).Range)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// Palette is the set of colors the board and UI text are drawn with
+type Palette struct {
+	Tiles        map[game.TileType]color.RGBA
+	GridLine     color.RGBA
+	Path         color.RGBA
+	NoPath       color.RGBA
+	Laser        color.RGBA
+	EnemyOutline color.RGBA // Ring around enemies; transparent for none
+	Text         color.RGBA
+	TextOutline  color.RGBA
+}
+
+// standardPalette is the default look
+var standardPalette = Palette{
+	Tiles: map[game.TileType]color.RGBA{
+		game.TileEmpty:  {R: 30, G: 30, B: 30, A: 255},    // Dark gray
+		game.TileGround: {R: 80, G: 60, B: 40, A: 255},    // Brown
+		game.TileWall:   {R: 100, G: 100, B: 100, A: 255}, // Gray
+		game.TileBase:   {R: 50, G: 100, B: 200, A: 255},  // Blue
+		game.TileSpawn:  {R: 200, G: 50, B: 50, A: 255},   // Red
+		game.TileTower:  {R: 50, G: 200, B: 50, A: 255},   // Green
+		game.TileMud:    {R: 55, G: 40, B: 25, A: 255},    // Dark brown
+		game.TileRoad:   {R: 150, G: 140, B: 120, A: 255}, // Pale gravel
+	},
+	GridLine:    color.RGBA{R: 60, G: 60, B: 60, A: 255},
+	Path:        color.RGBA{R: 255, G: 200, B: 50, A: 180},
+	NoPath:      color.RGBA{R: 255, G: 0, B: 0, A: 100},
+	Laser:       color.RGBA{R: 255, G: 255, B: 0, A: 255},
+	Text:        color.RGBA{R: 255, G: 255, B: 255, A: 255},
+	TextOutline: color.RGBA{R: 0, G: 0, B: 0, A: 160},
+}
+
+// highContrastPalette keeps tiles, path, and enemies as far apart in
+// brightness and hue as possible for low-vision players
+var highContrastPalette = Palette{
+	Tiles: map[game.TileType]color.RGBA{
+		game.TileEmpty:  {R: 0, G: 0, B: 0, A: 255},       // Black
+		game.TileGround: {R: 20, G: 20, B: 20, A: 255},    // Near black
+		game.TileWall:   {R: 255, G: 255, B: 255, A: 255}, // White
+		game.TileBase:   {R: 0, G: 160, B: 255, A: 255},   // Bright blue
+		game.TileSpawn:  {R: 255, G: 0, B: 255, A: 255},   // Magenta
+		game.TileTower:  {R: 0, G: 255, B: 0, A: 255},     // Bright green
+		game.TileMud:    {R: 90, G: 45, B: 0, A: 255},     // Deep orange-brown
+		game.TileRoad:   {R: 120, G: 120, B: 150, A: 255}, // Slate
+	},
+	GridLine:     color.RGBA{R: 110, G: 110, B: 110, A: 255},
+	Path:         color.RGBA{R: 255, G: 255, B: 0, A: 255},
+	NoPath:       color.RGBA{R: 255, G: 0, B: 0, A: 220},
+	Laser:        color.RGBA{R: 0, G: 255, B: 255, A: 255},
+	EnemyOutline: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+	Text:         color.RGBA{R: 255, G: 255, B: 255, A: 255},
+	TextOutline:  color.RGBA{R: 0, G: 0, B: 0, A: 255},
+}
+
+// palette returns the colors for the current accessibility settings
+func (g *Game) palette() *Palette {
+	if g.settings.HighContrast {
+		return &highContrastPalette
+	}
+	return &standardPalette
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var pickupColor = color.RGBA{R: 255, G: 215, B: 60, A: 255}
+
+// handlePickupInput collects the orb under a click (or tap) at a world
+// position. Returns true if one was collected, so the click does nothing else.
+func (g *Game) handlePickupInput(wx, wy float64, tap bool) bool {
+	if g.State != game.StatePlaying || !(inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || tap) {
+		return false
+	}
+	for i, p := range g.Pickups {
+		if math.Hypot(p.X-wx, p.Y-wy) <= game.PickupReach {
+			g.Resources += p.Value
+			g.AddFloatingText(p.X, p.Y, fmt.Sprintf("+%d", p.Value))
+			g.Pickups = append(g.Pickups[:i], g.Pickups[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// drawPickups draws pulsing orbs, blinking in their last second
+func (g *Game) drawPickups(screen *ebiten.Image) {
+	for _, p := range g.Pickups {
+		if p.TTL < game.TPS && p.TTL/6%2 == 0 {
+			continue
+		}
+		pulse := 0.5 + 0.5*math.Sin(float64(p.TTL)*0.2)
+		r := float32(game.PickupRadius * (0.8 + 0.2*pulse))
+		glow := pickupColor
+		glow.A = uint8(60 + 60*pulse)
+		vector.DrawFilledCircle(screen, float32(p.X), float32(p.Y), r*1.6, glow, true)
+		vector.DrawFilledCircle(screen, float32(p.X), float32(p.Y), r, pickupColor, true)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// drawProjectiles draws each shell as a small dot in its tower's shot color
+func (g *Game) drawProjectiles(screen *ebiten.Image) {
+	pal := g.palette()
+	for _, p := range g.Projectiles {
+		c := game.TowerTypes[p.Tower.Kind].LaserColor
+		if c.A == 0 {
+			c = pal.Laser
+		}
+		vector.DrawFilledCircle(screen, float32(p.X), float32(p.Y), game.ProjectileRadius, c, true)
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// drawRage tints a raging enemy red, deeper the stronger the rage
+func drawRage(screen *ebiten.Image, e *game.Enemy, rage float64) {
+	if rage <= 0 {
+		return
+	}
+	tint := color.RGBA{R: uint8(200 * rage / game.RageMaxBonus), A: uint8(160 * rage / game.RageMaxBonus)}
+	vector.DrawFilledCircle(screen, float32(e.X), float32(e.Y), float32(e.Radius()), tint, true)
+}
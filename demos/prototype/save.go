@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// handleSaveInput saves the game in progress on F2 and loads the saved one
+// on F4, keeping session-wide state like settings. In the map editor F2
+// saves the map instead. (F5 and F9 are taken by the freeze frame and flow
+// field toggles.)
+func (g *Game) handleSaveInput() {
+	switch {
+	case g.editing && inpututil.IsKeyJustPressed(ebiten.KeyF2):
+		if err := g.SaveCustomMap(); err != nil {
+			log.Printf("saving map: %v", err)
+			g.ShowMessage("Couldn't save the map")
+			return
+		}
+		g.ShowMessage("Map saved (Custom Map on the main menu plays it)")
+	case inpututil.IsKeyJustPressed(ebiten.KeyF2):
+		if err := g.SaveToDisk(); err != nil {
+			log.Printf("saving game: %v", err)
+			g.ShowMessage("Couldn't save the game")
+			return
+		}
+		g.ShowMessage("Game saved (F4 loads it)")
+	case inpututil.IsKeyJustPressed(ebiten.KeyF4):
+		ng, err := game.LoadFromDisk()
+		if err != nil {
+			log.Printf("loading game: %v", err)
+			g.ShowMessage("Couldn't load a saved game")
+			return
+		}
+		g.replaceWith(ng)
+		g.ShowMessage("Game loaded")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var selectionColor = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+var selectBoxColor = color.RGBA{R: 255, G: 255, B: 255, A: 40}
+
+// handleSelectionInput updates the tower selection from the mouse:
+// Ctrl+drag box-selects (add with Shift), Shift+click toggles one tower,
+// and a plain click on a tower selects just that tower.
+// Returns true if the left button was used for selecting, so it shouldn't place.
+func (g *Game) handleSelectionInput(mx, my int) bool {
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl)
+
+	if g.selecting {
+		if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+			g.selecting = false
+			if !shift {
+				g.clearSelection()
+			}
+			x0, y0, x1, y1 := g.selectionBox(mx, my)
+			for _, t := range g.Towers {
+				cx, cy := game.CellCenter(t.X, t.Y)
+				if cx >= x0 && cx <= x1 && cy >= y0 && cy <= y1 {
+					g.selected[t] = true
+				}
+			}
+		}
+		return true
+	}
+
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		// Keep the button from placing towers while a modifier is held
+		return ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) && (shift || ctrl)
+	}
+
+	if ctrl {
+		g.selecting = true
+		g.selectStartX, g.selectStartY = mx, my
+		return true
+	}
+
+	if !g.hoverValid {
+		return false
+	}
+	t := g.TowerAt(g.hoverX, g.hoverY)
+	switch {
+	case t != nil && shift:
+		if g.selected[t] {
+			delete(g.selected, t)
+		} else {
+			g.selected[t] = true
+		}
+		return true
+	case t != nil:
+		g.clearSelection()
+		g.selected[t] = true
+		return true
+	}
+	return shift
+}
+
+// selectionBox returns the drag box in pixels, normalized so x0<=x1 and y0<=y1
+func (g *Game) selectionBox(mx, my int) (x0, y0, x1, y1 float64) {
+	x0, x1 = float64(min(g.selectStartX, mx)), float64(max(g.selectStartX, mx))
+	y0, y1 = float64(min(g.selectStartY, my)), float64(max(g.selectStartY, my))
+	return x0, y0, x1, y1
+}
+
+// clearSelection deselects every tower
+func (g *Game) clearSelection() {
+	g.selected = make(map[*game.Tower]bool)
+}
+
+// selectedTowers returns the selected towers in placement order, so batch
+// operations never depend on map iteration order
+func (g *Game) selectedTowers() []*game.Tower {
+	var towers []*game.Tower
+	for _, t := range g.Towers {
+		if g.selected[t] {
+			towers = append(towers, t)
+		}
+	}
+	return towers
+}
+
+// sellSelected sells every selected tower with a single path recalculation
+func (g *Game) sellSelected() {
+	towers := g.selectedTowers()
+	if len(towers) == 0 {
+		return
+	}
+	before := g.Resources
+	for _, t := range towers {
+		g.RemoveTower(t.X, t.Y)
+	}
+	g.OnGridChanged(nil, true)
+	g.ShowMessage("Sold %d towers (+%d)", len(towers), g.Resources-before)
+}
+
+// handleGroupInput applies batch operations to the selection
+func (g *Game) handleGroupInput() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyDelete) || inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
+		g.sellSelected()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.clearSelection()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF) {
+		g.toggleManualFire()
+	}
+}
+
+// drawSelection outlines selected towers and the box being dragged
+func (g *Game) drawSelection(screen *ebiten.Image) {
+	for _, t := range g.selectedTowers() {
+		px := float32(t.X*game.CellSize) + 1
+		py := float32(t.Y*game.CellSize) + 1
+		vector.StrokeRect(screen, px, py, game.CellSize-2, game.CellSize-2, 2, selectionColor, false)
+	}
+	if g.selecting {
+		wx, wy := g.camera.toWorld(ebiten.CursorPosition())
+		x0, y0, x1, y1 := g.selectionBox(int(wx), int(wy))
+		w, h := float32(x1-x0), float32(y1-y0)
+		vector.DrawFilledRect(screen, float32(x0), float32(y0), w, h, selectBoxColor, false)
+		vector.StrokeRect(screen, float32(x0), float32(y0), w, h, 1, selectionColor, false)
+	}
+}
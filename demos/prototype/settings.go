@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+const (
+	VolumeStep = 0.1 // Volume change per +/- key press
+)
+
+// Settings holds player preferences that persist between sessions
+type Settings struct {
+	MasterVolume   float64 `json:"masterVolume"`   // 0..1, scales every sound
+	SFXVolume      float64 `json:"sfxVolume"`      // 0..1, scales sound effects
+	HighContrast   bool    `json:"highContrast"`   // Use the high-contrast palette
+	TextScale      float64 `json:"textScale"`      // UI text size multiplier
+	BulletTime     bool    `json:"bulletTime"`     // Slow motion when a boss dies
+	TracerTicks    int     `json:"tracerTicks"`    // Ticks a shot stays on screen
+	ShotTrails     bool    `json:"shotTrails"`     // Shots leave a faint fading trail
+	AutoStartWaves bool    `json:"autoStartWaves"` // Skip the planning pause between waves
+	FrameBudget    bool    `json:"frameBudget"`    // Defer fast-forward steps that would make a frame hitch
+}
+
+// defaultSettings returns the settings used when nothing has been saved yet
+func defaultSettings() Settings {
+	return Settings{
+		MasterVolume: 0.8,
+		SFXVolume:    1.0,
+		TextScale:    1.0,
+		BulletTime:   true,
+		TracerTicks:  game.LaserDuration,
+		FrameBudget:  true,
+	}
+}
+
+// syncSettings hands the simulation the settings it follows
+func (g *Game) syncSettings() {
+	g.TracerTicks = g.settings.TracerTicks
+	g.ShotTrails = g.settings.ShotTrails
+	g.AutoStartWaves = g.settings.AutoStartWaves
+}
+
+// settingsPath returns the file settings are persisted to
+func settingsPath() (string, error) {
+	return game.ConfigPath("settings.json")
+}
+
+// loadSettings reads saved settings, falling back to defaults if none exist
+func loadSettings() (Settings, error) {
+	s := defaultSettings()
+	path, err := settingsPath()
+	if err != nil {
+		return s, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return defaultSettings(), err
+	}
+	s.MasterVolume = clampVolume(s.MasterVolume)
+	s.SFXVolume = clampVolume(s.SFXVolume)
+	s.TextScale = clampTextScale(s.TextScale)
+	s.TracerTicks = clampTracerTicks(s.TracerTicks)
+	return s, nil
+}
+
+// save writes the settings to disk
+func (s Settings) save() error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// saveSession persists everything that should survive closing the game: the
+// settings, and a game in progress for Continue to resume. A finished game
+// clears the last session instead, and the untouched game behind the main
+// menu leaves it alone. Failures are logged rather than blocking the exit.
+func (g *Game) saveSession() {
+	if err := g.settings.save(); err != nil {
+		log.Printf("saving settings on exit: %v", err)
+	}
+	switch {
+	case g.State != game.StatePlaying:
+		if err := game.ClearSession(); err != nil {
+			log.Printf("clearing last session on exit: %v", err)
+		}
+	case g.Tick > 0:
+		if err := g.SaveSession(); err != nil {
+			log.Printf("saving last session on exit: %v", err)
+		}
+	}
+}
+
+// effectVolume returns the volume applied to sound effect players
+func (s Settings) effectVolume() float64 {
+	return s.MasterVolume * s.SFXVolume
+}
+
+// clampVolume keeps a volume within 0..1, snapped to VolumeStep
+func clampVolume(v float64) float64 {
+	v = math.Round(v/VolumeStep) * VolumeStep
+	return math.Max(0, math.Min(1, v))
+}
+
+// clampTextScale keeps a text scale within range, snapped to TextScaleStep
+func clampTextScale(v float64) float64 {
+	v = math.Round(v/TextScaleStep) * TextScaleStep
+	return math.Max(TextScaleMin, math.Min(TextScaleMax, v))
+}
+
+// adjustVolume changes master volume, or SFX volume when sfx is true, and persists it
+func (g *Game) adjustVolume(delta float64, sfx bool) {
+	if sfx {
+		g.settings.SFXVolume = clampVolume(g.settings.SFXVolume + delta)
+		g.ShowMessage("SFX volume: %.0f%%", g.settings.SFXVolume*100)
+	} else {
+		g.settings.MasterVolume = clampVolume(g.settings.MasterVolume + delta)
+		g.ShowMessage("Master volume: %.0f%%", g.settings.MasterVolume*100)
+	}
+	g.sound.setVolume(g.settings.effectVolume())
+	g.saveSettings()
+}
+
+// adjustTextScale grows or shrinks UI text and persists it
+func (g *Game) adjustTextScale(delta float64) {
+	g.settings.TextScale = clampTextScale(g.settings.TextScale + delta)
+	g.ShowMessage("Text size: %.0f%%", g.settings.TextScale*100)
+	g.saveSettings()
+}
+
+// saveSettings persists settings after a change, logging failures
+func (g *Game) saveSettings() {
+	if err := g.settings.save(); err != nil {
+		log.Printf("saving settings: %v", err)
+	}
+}
+
+// handleSettingsInput adjusts volumes with +/- (hold Shift for SFX instead of master)
+// and text size with [ and ]
+func (g *Game) handleSettingsInput() {
+	sfx := ebiten.IsKeyPressed(ebiten.KeyShift)
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadAdd) {
+		g.adjustVolume(VolumeStep, sfx)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadSubtract) {
+		g.adjustVolume(-VolumeStep, sfx)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketRight) {
+		g.adjustTextScale(TextScaleStep)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft) {
+		g.adjustTextScale(-TextScaleStep)
+	}
+}
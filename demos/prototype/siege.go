@@ -0,0 +1,36 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var towerHPColor = color.RGBA{R: 230, G: 230, B: 230, A: 255}
+
+var strikeColor = color.RGBA{R: 255, G: 60, B: 60, A: 255}
+
+// drawSiege draws damaged towers' health bars and the strikes of attacking enemies
+func (g *Game) drawSiege(screen *ebiten.Image) {
+	if !g.SiegeEnabled {
+		return
+	}
+	for _, t := range g.Towers {
+		if t.HP >= game.TowerMaxHP {
+			continue
+		}
+		px := float32(t.X*game.CellSize) + 4
+		py := float32(t.Y*game.CellSize) + game.CellSize - 7
+		w := float32(game.CellSize - 8)
+		vector.DrawFilledRect(screen, px, py, w, 3, color.RGBA{60, 60, 60, 255}, false)
+		vector.DrawFilledRect(screen, px, py, w*float32(t.HP/game.TowerMaxHP), 3, towerHPColor, false)
+	}
+	for _, e := range g.Enemies {
+		if t := g.BlockingTower(e); t != nil {
+			cx, cy := game.CellCenter(t.X, t.Y)
+			vector.StrokeLine(screen, float32(e.X), float32(e.Y), float32(cx), float32(cy), 2, strikeColor, true)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// PlacementScore caches how many gap cells a placement would cover, so it's
+// only counted again when the hovered cell, the kind, or the gaps change
+type PlacementScore struct {
+	Valid bool
+	Cell  game.Point
+	Kind  game.TowerKind
+	Score int
+}
+
+// placementScore returns GapsCovered for a tower of the selected kind at
+// (x, y), counting again only when the cell, the kind, or the gaps change
+func (g *Game) placementScore(x, y int) int {
+	cell := game.Point{X: x, Y: y}
+	if g.placement.Valid && g.placement.Cell == cell && g.placement.Kind == g.selectedKind {
+		return g.placement.Score
+	}
+	score := g.GapsCovered(x, y, g.selectedKind)
+	g.placement = PlacementScore{Valid: true, Cell: cell, Kind: g.selectedKind, Score: score}
+	return score
+}
+
+// drawPlacementScore labels the placement ghost with its coverage score
+// (towers that never deal damage don't cover anything)
+func (g *Game) drawPlacementScore(screen *ebiten.Image, x, y int) {
+	if game.TowerTypes[g.selectedKind].Damage == 0 || g.PathBlocked {
+		return
+	}
+	text := fmt.Sprintf("+%d", g.placementScore(x, y))
+	cx, cy := game.CellCenter(x, y)
+	g.drawText(screen, text, int(cx)-g.textWidth(text)/2, int(cy)-g.lineHeight()/2)
+}
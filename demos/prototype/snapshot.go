@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// saveSnapshot renders the board's layout (tiles, towers, tar, and path, but
+// no enemies or UI) at full size, whatever the camera shows, and writes it
+// to a timestamped PNG in the working directory. Returns the file name.
+func (g *Game) saveSnapshot() (string, error) {
+	img := ebiten.NewImage(g.WorldWidth(), g.WorldHeight())
+	defer img.Deallocate()
+	g.drawTiles(img)
+	g.drawGridLines(img)
+	g.drawTar(img)
+	g.drawPath(img)
+
+	name := fmt.Sprintf("claude-td-%s.png", time.Now().Format("20060102-150405"))
+	f, err := os.Create(name)
+	if err != nil {
+		return "", err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return "", err
+	}
+	return name, f.Close()
+}
+
+// handleSnapshotInput saves a board snapshot when F12 is pressed
+func (g *Game) handleSnapshotInput() {
+	if !inpututil.IsKeyJustPressed(ebiten.KeyF12) {
+		return
+	}
+	name, err := g.saveSnapshot()
+	if err != nil {
+		log.Printf("saving snapshot: %v", err)
+		g.ShowMessage("Snapshot failed: %v", err)
+		return
+	}
+	g.ShowMessage("Saved %s", name)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+const (
+	MaxGameSpeed   = 4                     // Fastest fast-forward, in ticks per frame
+	FrameBudget    = 12 * time.Millisecond // Simulation time per frame before extra steps wait
+	MaxStepBacklog = 2 * MaxGameSpeed      // Most deferred steps carried over; beyond that they're dropped
+)
+
+// handleSpeedInput steps fast-forward through 1x, 2x, and 4x: Tab speeds
+// up and Shift+Tab slows down. (+ and - are the volume keys.)
+func (g *Game) handleSpeedInput() {
+	if !inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		return
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		g.stepSpeed(-1)
+	} else {
+		g.stepSpeed(1)
+	}
+	g.ShowMessage("Speed: %dx (Tab faster, Shift+Tab slower)", g.speed)
+}
+
+// stepSpeed doubles the game speed for dir > 0 and halves it otherwise,
+// wrapping around between 1x and MaxGameSpeed
+func (g *Game) stepSpeed(dir int) {
+	switch speed := g.gameSpeed(); {
+	case dir > 0 && speed < MaxGameSpeed:
+		g.speed = speed * 2
+	case dir > 0:
+		g.speed = 1
+	case speed > 1:
+		g.speed = speed / 2
+	default:
+		g.speed = MaxGameSpeed
+	}
+}
+
+// gameSpeed returns the fast-forward multiplier
+func (g *Game) gameSpeed() int {
+	return max(g.speed, 1)
+}
+
+// runSteps runs a frame's simulation steps, the first carrying the frame's
+// input (which still lands, holding time still, on a frame with no steps).
+// The first step always runs, so 1x play is unaffected; under fast-forward
+// with the frame budget on, steps that would push the frame past
+// FrameBudget are deferred to later frames instead, keeping the window
+// responsive during huge waves. Every step is still a whole tick, so
+// deferring only changes when ticks run, never what they do.
+func (g *Game) runSteps(n int, in game.Input) {
+	if n == 0 {
+		in.Hold = true
+		g.Step(in)
+	}
+	start := time.Now()
+	for i := range n {
+		if g.State != game.StatePlaying {
+			return
+		}
+		if i > 0 && g.settings.FrameBudget && time.Since(start) > FrameBudget {
+			deferred := n - i
+			g.stepCarry = min(g.stepCarry+float64(deferred), MaxStepBacklog)
+			if g.ShowDebug {
+				log.Printf("frame budget: ran %d of %d steps in %v, deferring %d", i, n, time.Since(start), deferred)
+			}
+			g.stepsDeferred = deferred
+			return
+		}
+		g.Step(in)
+		in = game.Input{}
+	}
+	g.stepsDeferred = 0
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+func TestStepSpeedWrapsBothWays(t *testing.T) {
+	g := &Game{}
+	for _, tc := range []struct {
+		dir  int
+		want int
+	}{
+		{dir: 1, want: 2},
+		{dir: 1, want: 4},
+		{dir: 1, want: 1},
+		{dir: -1, want: 4},
+		{dir: -1, want: 2},
+		{dir: -1, want: 1},
+	} {
+		g.stepSpeed(tc.dir)
+		if got := g.gameSpeed(); got != tc.want {
+			t.Fatalf("speed after stepping %+d = %dx, want %dx", tc.dir, got, tc.want)
+		}
+	}
+}
+
+func TestRestartResetsSpeedAndFreeze(t *testing.T) {
+	g := &Game{}
+	g.replaceWith(game.NewGameSeed(1))
+	g.speed, g.frozen = MaxGameSpeed, true
+
+	g.restart()
+
+	if g.gameSpeed() != 1 || g.frozen {
+		t.Errorf("after restart speed = %dx and frozen = %v, want 1x and false", g.gameSpeed(), g.frozen)
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// drawExplosions draws each explosion as a ring growing out to its radius
+// while it fades
+func (g *Game) drawExplosions(screen *ebiten.Image) {
+	for _, x := range g.Explosions {
+		f := 1 - float64(x.TTL)/game.ExplosionDuration
+		c := x.Color
+		c.A = uint8(float64(c.A) * (1 - f))
+		vector.StrokeCircle(screen, float32(x.X), float32(x.Y), float32(x.Radius*f), 2, c, true)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// Enemy kinds with a final sprint speed up for their last few steps to the
+// base. No built-in kind sprints; it's for wave designs that want a tense
+// finish, set per kind with EnemyType.SprintSteps and SprintSpeed.
+
+var streakColor = color.RGBA{R: 255, G: 255, B: 255, A: 110}
+
+// drawStreaks trails motion lines behind a sprinting enemy
+func (g *Game) drawStreaks(screen *ebiten.Image, e *game.Enemy) {
+	if !e.Sprinting() {
+		return
+	}
+	vx, vy := g.VelocityAt(e)
+	dx, dy := game.Unit(vx, vy)
+	px, py := -dy, dx // Perpendicular, to spread the streaks
+	for _, off := range []float64{-game.EnemyRadius / 2, 0, game.EnemyRadius / 2} {
+		x0, y0 := e.X-dx*game.EnemyRadius+px*off, e.Y-dy*game.EnemyRadius+py*off
+		x1, y1 := x0-dx*game.EnemyRadius*1.5, y0-dy*game.EnemyRadius*1.5
+		vector.StrokeLine(screen, float32(x0), float32(y0), float32(x1), float32(y1), 2, streakColor, true)
+	}
+}
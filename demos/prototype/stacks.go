@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var stackGlowColor = color.RGBA{R: 255, G: 200, B: 80, A: 255}
+
+// drawKillStacks labels leveling towers with their stack count, glowing
+// brighter the closer they are to the cap
+func (g *Game) drawKillStacks(screen *ebiten.Image) {
+	for _, t := range g.Towers {
+		maxStacks := game.TowerTypes[t.Kind].MaxStacks
+		if maxStacks == 0 || t.KillStacks == 0 {
+			continue
+		}
+		cx, cy := game.CellCenter(t.X, t.Y)
+		glow := stackGlowColor
+		glow.A = uint8(180 * t.KillStacks / maxStacks)
+		vector.StrokeRect(screen, float32(t.X*game.CellSize)+1, float32(t.Y*game.CellSize)+1, game.CellSize-2, game.CellSize-2, 3, glow, false)
+		text := fmt.Sprint(t.KillStacks)
+		g.drawText(screen, text, int(cx)-g.textWidth(text)/2, int(cy)-g.lineHeight()/2)
+	}
+}
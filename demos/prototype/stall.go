@@ -0,0 +1,32 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var stallBorderColor = color.RGBA{R: 255, G: 60, B: 60, A: 255}
+
+const stallWarning = "Path blocked - enemies can't spawn, sell a tower"
+
+// drawSpawnStall shows the blocked path warning mid-screen. Past the grace
+// period it blinks, with a red border around the board.
+func (g *Game) drawSpawnStall(screen *ebiten.Image) {
+	if g.SpawnStall == 0 || g.State != game.StatePlaying {
+		return
+	}
+	urgent := g.SpawnStall >= game.StallGrace
+	if urgent && g.SpawnStall/(game.TPS/4)%2 == 0 {
+		return
+	}
+	if urgent {
+		vector.StrokeRect(screen, 2, 2, float32(g.screenWidth()-4), float32(g.viewHeight()-4), 4, stallBorderColor, false)
+	}
+	w, lh := g.textWidth(stallWarning), g.lineHeight()
+	x, y := (g.screenWidth()-w)/2, g.viewHeight()/3
+	vector.DrawFilledRect(screen, float32(x-lh/2), float32(y-lh/4), float32(w+lh), float32(lh+lh/2), menuPanelColor, false)
+	g.drawText(screen, stallWarning, x, y)
+}
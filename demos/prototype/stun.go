@@ -0,0 +1,25 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var stunColor = color.RGBA{R: 255, G: 240, B: 120, A: 255}
+
+// drawStun circles a stunned enemy's head with spinning stars
+func drawStun(screen *ebiten.Image, e *game.Enemy) {
+	if e.Stunned <= 0 {
+		return
+	}
+	for i := range 3 {
+		a := float64(e.Stunned)*0.15 + float64(i)*2*math.Pi/3
+		x := float32(e.X + math.Cos(a)*e.Radius())
+		y := float32(e.Y - e.Radius() + math.Sin(a)*e.Radius()/3)
+		vector.DrawFilledCircle(screen, x, y, 2.5, stunColor, true)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+const TargetModeKey = ebiten.KeyQuote
+
+// handleTargetModeInput switches the hovered tower to its next targeting
+// mode on TargetModeKey. It drops its current target, so the new mode
+// takes effect right away.
+func (g *Game) handleTargetModeInput() {
+	if !g.hoverValid || !inpututil.IsKeyJustPressed(TargetModeKey) {
+		return
+	}
+	t := g.TowerAt(g.hoverX, g.hoverY)
+	if t == nil || !canFireManually(t.Kind) {
+		return
+	}
+	t.TargetMode = t.TargetMode.Next()
+	t.Target = nil
+	g.ShowMessage("%s targets: %s", game.TowerTypes[t.Kind].Name, t.TargetMode)
+}
+
+// drawTargetModes labels each targeting tower with its mode's initial in
+// the bottom-left corner
+func (g *Game) drawTargetModes(screen *ebiten.Image) {
+	for _, t := range g.Towers {
+		if !canFireManually(t.Kind) {
+			continue
+		}
+		g.drawText(screen, t.TargetMode.String()[:1], t.X*game.CellSize+3, (t.Y+1)*game.CellSize-g.lineHeight())
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var tarColor = color.RGBA{R: 20, G: 15, B: 10, A: 110}
+
+// drawTar darkens every slowed cell
+func (g *Game) drawTar(screen *ebiten.Image) {
+	for y := range g.Slow {
+		for x := range g.Slow[y] {
+			if g.Slow[y][x] == 0 {
+				continue
+			}
+			vector.DrawFilledRect(screen, float32(x*game.CellSize), float32(y*game.CellSize), game.CellSize, game.CellSize, tarColor, false)
+		}
+	}
+}
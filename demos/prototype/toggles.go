@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// Toggle is an on/off option with a hotkey, also listed in the settings menu
+type Toggle struct {
+	Name   string
+	Key    ebiten.Key
+	Detail func() string // Extra explanation shown when switched on; may be nil
+	Get    func(g *Game) bool
+	Set    func(g *Game, on bool)
+}
+
+// toggles lists every on/off option, in settings menu order
+var toggles = []Toggle{
+	{
+		Name: "High contrast", Key: ebiten.KeyH,
+		Get: func(g *Game) bool { return g.settings.HighContrast },
+		Set: func(g *Game, on bool) {
+			g.settings.HighContrast = on
+			g.saveSettings()
+		},
+	},
+	{
+		Name: "Bullet time", Key: ebiten.KeyU,
+		Detail: func() string { return "slow motion on boss kills" },
+		Get:    func(g *Game) bool { return g.settings.BulletTime },
+		Set: func(g *Game, on bool) {
+			g.settings.BulletTime = on
+			g.saveSettings()
+		},
+	},
+	{
+		Name: "Line of sight", Key: ebiten.KeyL,
+		Detail: func() string { return "walls block shots" },
+		Get:    func(g *Game) bool { return g.RequireLineOfSight },
+		Set: func(g *Game, on bool) {
+			g.RequireLineOfSight = on
+			g.CoverageDirty = true
+		},
+	},
+	{
+		Name: "Skip doomed", Key: ebiten.KeyY,
+		Detail: func() string { return "towers ignore enemies incoming damage will kill" },
+		Get:    func(g *Game) bool { return g.SkipDoomed },
+		Set:    func(g *Game, on bool) { g.SkipDoomed = on },
+	},
+	{
+		Name: "Coverage gaps", Key: ebiten.KeyC,
+		Get: func(g *Game) bool { return g.showGaps },
+		Set: func(g *Game, on bool) { g.showGaps = on },
+	},
+	{
+		Name: "Danger rings", Key: ebiten.KeyV,
+		Get: func(g *Game) bool { return g.showDanger },
+		Set: func(g *Game, on bool) { g.showDanger = on },
+	},
+	{
+		Name: "Base alarm", Key: ebiten.KeyB,
+		Detail: func() string { return "beeps when an enemy nears the base" },
+		Get:    func(g *Game) bool { return g.AlarmEnabled },
+		Set:    func(g *Game, on bool) { g.AlarmEnabled = on },
+	},
+	{
+		Name: "Minimap", Key: ebiten.KeyN,
+		Get: func(g *Game) bool { return g.showMinimap },
+		Set: func(g *Game, on bool) { g.showMinimap = on },
+	},
+	{
+		Name: "Endless mode", Key: ebiten.KeyE,
+		Detail: func() string { return fmt.Sprintf("mega wave every %d waves", game.MegaWaveInterval) },
+		Get:    func(g *Game) bool { return g.Endless },
+		Set:    func(g *Game, on bool) { g.Endless = on },
+	},
+	{
+		Name: "Surge", Key: ebiten.KeyG,
+		Detail: func() string { return "easy waves get reinforcements" },
+		Get:    func(g *Game) bool { return g.SurgeEnabled },
+		Set:    func(g *Game, on bool) { g.SurgeEnabled = on },
+	},
+	{
+		Name: "Overkill carry", Key: ebiten.KeyO,
+		Detail: func() string { return fmt.Sprintf("wasted damage pays %.0f%%", game.OverkillRewardRate*100) },
+		Get:    func(g *Game) bool { return g.OverkillEnabled },
+		Set:    func(g *Game, on bool) { g.OverkillEnabled = on },
+	},
+	{
+		Name: "Siege", Key: ebiten.KeyK,
+		Detail: func() string { return "enemies attack towers in their way" },
+		Get:    func(g *Game) bool { return g.SiegeEnabled },
+		Set: func(g *Game, on bool) {
+			g.SiegeEnabled = on
+			if g.SmoothPaths {
+				g.RecalculateEnemyPaths(nil) // Siege routes aren't smoothed
+			}
+		},
+	},
+	{
+		Name: "Smooth paths", Key: ebiten.KeyA,
+		Detail: func() string { return "enemies cut straight across open ground" },
+		Get:    func(g *Game) bool { return g.SmoothPaths },
+		Set: func(g *Game, on bool) {
+			g.SmoothPaths = on
+			g.RecalculateEnemyPaths(nil) // Reroute everyone the new way
+		},
+	},
+	{
+		Name: "Pickups", Key: ebiten.KeyP,
+		Detail: func() string {
+			return fmt.Sprintf("dying enemies may drop orbs worth %d; click to collect", game.PickupValue)
+		},
+		Get: func(g *Game) bool { return g.PickupsEnabled },
+		Set: func(g *Game, on bool) { g.PickupsEnabled = on },
+	},
+	{
+		Name: "Rage", Key: ebiten.KeyI,
+		Detail: func() string {
+			return fmt.Sprintf("a wave's last %d enemies get up to %.0f%% faster", game.RageSurvivors, game.RageMaxBonus*100)
+		},
+		Get: func(g *Game) bool { return g.RageEnabled },
+		Set: func(g *Game, on bool) { g.RageEnabled = on },
+	},
+	{
+		Name: "Formations", Key: ebiten.KeyZ,
+		Detail: func() string {
+			return fmt.Sprintf("grouped enemies take up to %.0f%% less damage", game.FormationMaxGuard*100)
+		},
+		Get: func(g *Game) bool { return g.FormationsEnabled },
+		Set: func(g *Game, on bool) { g.FormationsEnabled = on },
+	},
+	{
+		Name: "Realistic economy", Key: ebiten.KeyJ,
+		Detail: func() string { return "hot towers sell for less" },
+		Get:    func(g *Game) bool { return g.RealisticEconomy },
+		Set:    func(g *Game, on bool) { g.RealisticEconomy = on },
+	},
+	{
+		Name: "Build queue", Key: ebiten.KeyQ,
+		Detail: func() string { return "clicks queue towers, built as resources allow" },
+		Get:    func(g *Game) bool { return g.BuildQueueEnabled },
+		Set: func(g *Game, on bool) {
+			g.BuildQueueEnabled = on
+			g.BuildQueue = nil
+		},
+	},
+	{
+		Name: "Tower limit", Key: ebiten.KeyX,
+		Detail: func() string { return fmt.Sprintf("max %d towers", game.TowerLimit) },
+		Get:    func(g *Game) bool { return g.MaxTowers > 0 },
+		Set: func(g *Game, on bool) {
+			g.MaxTowers = 0
+			if on {
+				g.MaxTowers = game.TowerLimit
+			}
+		},
+	},
+	{
+		Name: "Auto-start waves", Key: ebiten.KeyF7,
+		Detail: func() string { return "waves follow back to back, no planning pause" },
+		Get:    func(g *Game) bool { return g.settings.AutoStartWaves },
+		Set: func(g *Game, on bool) {
+			g.settings.AutoStartWaves = on
+			g.saveSettings()
+		},
+	},
+	{
+		Name: "Shot trails", Key: ebiten.KeyF6,
+		Detail: func() string { return "shots leave a fading trace" },
+		Get:    func(g *Game) bool { return g.settings.ShotTrails },
+		Set: func(g *Game, on bool) {
+			g.settings.ShotTrails = on
+			g.saveSettings()
+		},
+	},
+	{
+		Name: "Frame budget", Key: ebiten.KeyF8,
+		Detail: func() string { return fmt.Sprintf("fast-forward defers steps past %v a frame", FrameBudget) },
+		Get:    func(g *Game) bool { return g.settings.FrameBudget },
+		Set: func(g *Game, on bool) {
+			g.settings.FrameBudget = on
+			g.saveSettings()
+		},
+	},
+	{
+		Name: "Freeze frame", Key: ebiten.KeyF5,
+		Detail: func() string { return "same as Space; press . to advance one tick" },
+		Get:    func(g *Game) bool { return g.frozen },
+		Set:    func(g *Game, on bool) { g.frozen = on },
+	},
+	{
+		Name: "Flow field", Key: ebiten.KeyF9,
+		Detail: func() string { return "arrows toward the base" },
+		Get:    func(g *Game) bool { return g.showFlow },
+		Set: func(g *Game, on bool) {
+			g.showFlow = on
+			g.Flow = nil
+		},
+	},
+	{
+		Name: "Enemy separation", Key: ebiten.KeyF10,
+		Detail: func() string { return "crowded enemies spread across their path" },
+		Get:    func(g *Game) bool { return g.Separation },
+		Set:    func(g *Game, on bool) { g.Separation = on },
+	},
+	{
+		Name: "Map editor", Key: ebiten.KeyF11,
+		Detail: func() string { return "paint walls and move the spawn and base" },
+		Get:    func(g *Game) bool { return g.editing },
+		Set:    func(g *Game, on bool) { g.editing = on },
+	},
+	{
+		Name: "Debug overlay", Key: ebiten.KeyF3,
+		Get: func(g *Game) bool { return g.ShowDebug },
+		Set: func(g *Game, on bool) {
+			g.ShowDebug = on
+			g.RecalculatePath() // Collect (or drop) the explored cells
+		},
+	},
+}
+
+// flip switches a toggle and reports its new state
+func (t Toggle) flip(g *Game) {
+	on := !t.Get(g)
+	t.Set(g, on)
+	g.ShowMessage("%s", t.status(g))
+}
+
+// status describes a toggle's current state, e.g. "Surge: ON (easy waves get reinforcements)"
+func (t Toggle) status(g *Game) string {
+	if !t.Get(g) {
+		return t.Name + ": OFF"
+	}
+	if t.Detail != nil {
+		return fmt.Sprintf("%s: ON (%s)", t.Name, t.Detail())
+	}
+	return t.Name + ": ON"
+}
+
+// handleToggleInput flips any toggle whose hotkey was pressed
+func (g *Game) handleToggleInput() {
+	for _, t := range toggles {
+		if inpututil.IsKeyJustPressed(t.Key) {
+			t.flip(g)
+		}
+	}
+}
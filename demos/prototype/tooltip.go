@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+// towerModifiers describes everything currently changing what a tower does,
+// and anything keeping it from firing, one line each
+func (g *Game) towerModifiers(t *game.Tower) []string {
+	tt := game.TowerTypes[t.Kind]
+	secs := func(ticks int) float64 { return float64(ticks) / game.TPS }
+	var mods []string
+	if t.BuildTimer > 0 {
+		mods = append(mods, fmt.Sprintf("under construction %.1fs", secs(t.BuildTimer)))
+	}
+	if t.Level > 1 {
+		levels := float64(t.Level - 1)
+		mods = append(mods, fmt.Sprintf("level %d: x%.2f damage, x%.2f range", t.Level, 1+game.LevelDamageBonus*levels, 1+game.LevelRangeBonus*levels))
+	}
+	if t.KillStacks > 0 {
+		mods = append(mods, fmt.Sprintf("+%.1f damage (%d kill stacks)", tt.StackDamage*float64(t.KillStacks), t.KillStacks))
+	}
+	if t.BeamCharge > 0 {
+		mods = append(mods, fmt.Sprintf("beam charged +%.0f%% damage", t.BeamCharge*100))
+	}
+	if t.ManualFire {
+		mods = append(mods, fmt.Sprintf("manual fire: x%.0f damage, waits for a click", game.ManualFireBonus))
+	}
+	if tt.Shots > 0 {
+		mods = append(mods, fmt.Sprintf("%d of %d shots left", t.ShotsRemaining, tt.Shots))
+	}
+	if g.SiegeEnabled && t.HP < game.TowerMaxHP {
+		mods = append(mods, fmt.Sprintf("damaged: %.0f/%.0f HP", t.HP, game.TowerMaxHP))
+	}
+	if t.BuildTimer == 0 && t.Cooldown > 0 {
+		mods = append(mods, fmt.Sprintf("reloading %.1fs", secs(t.Cooldown)))
+	}
+	if t.BuildTimer == 0 && (tt.Damage > 0 || tt.Chill > 0) && !tt.Traps && tt.Arc == 0 && t.Target == nil {
+		mods = append(mods, "no target in range")
+	}
+	if len(mods) == 0 {
+		mods = append(mods, "no modifiers")
+	}
+	return mods
+}
+
+// drawTowerTooltip lists the modifiers of the selected tower beside it, when
+// exactly one is selected
+func (g *Game) drawTowerTooltip(screen *ebiten.Image) {
+	towers := g.selectedTowers()
+	if len(towers) != 1 {
+		return
+	}
+	t := towers[0]
+	lines := append([]string{game.TowerTypes[t.Kind].Name}, g.towerModifiers(t)...)
+	w := 0
+	for _, l := range lines {
+		w = max(w, g.textWidth(l))
+	}
+	lh := g.lineHeight()
+	w, h := w+8, len(lines)*lh+4
+
+	// Just right of the tower, kept on screen
+	wx, wy := game.CellCenter(t.X+1, t.Y)
+	sx, sy := g.camera.toScreen(wx-game.CellSize/2, wy-game.CellSize/2)
+	x := min(int(sx)+4, g.screenWidth()-w)
+	y := min(int(sy), g.viewHeight()-h)
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), menuPanelColor, false)
+	for i, l := range lines {
+		g.drawText(screen, l, x+4, y+2+i*lh)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// LongPressTicks is how long a touch must be held to count as a long press
+const LongPressTicks = 30
+
+// TouchState follows the first active touch. Other fingers are ignored
+// until it lifts.
+type TouchState struct {
+	ID     ebiten.TouchID
+	Active bool // A finger is down (or lifted this tick)
+	X, Y   int  // Last screen position
+	Ticks  int  // Ticks held so far
+}
+
+// updateTouch tracks the first touch and reports what it did this tick: a
+// tap (lifted before LongPressTicks) acts like a left click, a long press
+// (held for LongPressTicks) like a right click.
+func (g *Game) updateTouch() (tap, longPress bool) {
+	ts := &g.touch
+	if ts.Active && inpututil.IsTouchJustReleased(ts.ID) {
+		ts.Active = false
+		return ts.Ticks < LongPressTicks, false
+	}
+	if !ts.Active {
+		ids := inpututil.AppendJustPressedTouchIDs(nil)
+		if len(ids) == 0 {
+			return false, false
+		}
+		*ts = TouchState{ID: ids[0], Active: true}
+	}
+	ts.X, ts.Y = ebiten.TouchPosition(ts.ID)
+	ts.Ticks++
+	return false, ts.Ticks == LongPressTicks
+}
+
+// pointerPosition returns the screen position being pointed at: the
+// tracked touch while there is one, otherwise the mouse cursor
+func (g *Game) pointerPosition(tap bool) (int, int) {
+	if g.touch.Active || tap {
+		return g.touch.X, g.touch.Y
+	}
+	return ebiten.CursorPosition()
+}
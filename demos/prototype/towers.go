@@ -0,0 +1,160 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var facingColor = color.RGBA{R: 255, G: 255, B: 255, A: 200}
+
+var readyColor = color.RGBA{R: 80, G: 255, B: 80, A: 255}
+
+var chargingColor = color.RGBA{R: 90, G: 90, B: 90, A: 255}
+
+var constructionColor = color.RGBA{R: 0, G: 0, B: 0, A: 140}
+
+var buildRingColor = color.RGBA{R: 255, G: 255, B: 255, A: 230}
+
+// handleFacingInput rotates selected directional towers with the scroll wheel.
+// Returns true if any tower turned, so the wheel doesn't also zoom.
+func (g *Game) handleFacingInput() bool {
+	_, wheel := ebiten.Wheel()
+	if wheel == 0 {
+		return false
+	}
+	step := game.FacingStep
+	if wheel < 0 {
+		step = -game.FacingStep
+	}
+	rotated := false
+	for _, t := range g.selectedTowers() {
+		if game.TowerTypes[t.Kind].Arc > 0 {
+			t.Facing = math.Remainder(t.Facing+step, 2*math.Pi)
+			rotated = true
+		}
+	}
+	return rotated
+}
+
+// drawFacings draws a pointer and cone edges on every directional tower
+func (g *Game) drawFacings(screen *ebiten.Image) {
+	for _, t := range g.Towers {
+		arc := game.TowerTypes[t.Kind].Arc
+		if arc == 0 {
+			continue
+		}
+		cx, cy := game.CellCenter(t.X, t.Y)
+		x, y := float32(cx), float32(cy)
+		tip := float32(game.CellSize / 2)
+		vector.StrokeLine(screen, x, y, x+tip*float32(math.Cos(t.Facing)), y+tip*float32(math.Sin(t.Facing)), 3, facingColor, true)
+		for _, edge := range []float64{t.Facing - arc, t.Facing + arc} {
+			r := float32(game.CellSize / 3)
+			vector.StrokeLine(screen, x, y, x+r*float32(math.Cos(edge)), y+r*float32(math.Sin(edge)), 1, facingColor, true)
+		}
+	}
+}
+
+// canFireManually returns true if a tower kind shoots a single target, so
+// it makes sense to trigger it by hand
+func canFireManually(kind game.TowerKind) bool {
+	k := game.TowerTypes[kind]
+	return !k.Traps && k.Arc == 0 && k.Slow == 0 && !k.Bounce
+}
+
+// toggleManualFire switches the selected towers between auto and manual fire
+func (g *Game) toggleManualFire() {
+	towers := g.selectedTowers()
+	if len(towers) == 0 {
+		return
+	}
+	// Follow the first tower so a mixed group ends up all one way
+	manual := !towers[0].ManualFire
+	n := 0
+	for _, t := range towers {
+		if canFireManually(t.Kind) {
+			t.ManualFire = manual
+			t.FireQueued = false
+			n++
+		}
+	}
+	if manual {
+		g.ShowMessage("Manual fire: %d towers (x%.0f damage, click to shoot)", n, game.ManualFireBonus)
+	} else {
+		g.ShowMessage("Auto fire: %d towers", n)
+	}
+}
+
+// handleManualFireInput queues a shot when a manual tower is plainly clicked.
+// Returns true if the click was used, so it doesn't also select or place.
+func (g *Game) handleManualFireInput() bool {
+	if !g.hoverValid || !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
+		ebiten.IsKeyPressed(ebiten.KeyShift) || ebiten.IsKeyPressed(ebiten.KeyControl) {
+		return false
+	}
+	t := g.TowerAt(g.hoverX, g.hoverY)
+	if t == nil || !t.ManualFire {
+		return false
+	}
+	t.FireQueued = true
+	return true
+}
+
+// drawManualFire marks manual towers with a light: green when a click would
+// fire right now, gray while reloading or without a target
+func (g *Game) drawManualFire(screen *ebiten.Image) {
+	for _, t := range g.Towers {
+		if !t.ManualFire {
+			continue
+		}
+		x := float32(t.X*game.CellSize) + game.CellSize - 7
+		y := float32(t.Y*game.CellSize) + 7
+		c := chargingColor
+		if t.Cooldown == 0 && g.CanTarget(t, g.EffectiveStats(t), t.Target) {
+			c = readyColor
+		}
+		vector.DrawFilledCircle(screen, x, y, 4, c, true)
+		if t.FireQueued {
+			vector.StrokeCircle(screen, x, y, 6, 1, readyColor, true)
+		}
+	}
+}
+
+// drawConstruction shades towers that are still being built and shows a
+// ring that fills clockwise as construction progresses
+func (g *Game) drawConstruction(screen *ebiten.Image) {
+	for _, t := range g.Towers {
+		if t.BuildTimer <= 0 {
+			continue
+		}
+		px := float32(t.X * game.CellSize)
+		py := float32(t.Y * game.CellSize)
+		vector.DrawFilledRect(screen, px, py, game.CellSize, game.CellSize, constructionColor, false)
+
+		progress := 1 - float32(t.BuildTimer)/game.TowerBuildTime
+		start := float32(-math.Pi / 2)
+		var ring vector.Path
+		ring.Arc(px+game.CellSize/2, py+game.CellSize/2, game.CellSize/3, start, start+2*math.Pi*progress, vector.Clockwise)
+		op := &vector.DrawPathOptions{AntiAlias: true}
+		op.ColorScale.ScaleWithColor(buildRingColor)
+		vector.StrokePath(screen, &ring, &vector.StrokeOptions{Width: 3}, op)
+	}
+}
+
+// drawShotPips shows the shots left on towers that get used up, as a row of dots
+func (g *Game) drawShotPips(screen *ebiten.Image) {
+	for _, t := range g.Towers {
+		if game.TowerTypes[t.Kind].Shots == 0 {
+			continue
+		}
+		for i := range t.ShotsRemaining {
+			x := float32(t.X*game.CellSize) + 5 + float32(i)*6
+			y := float32(t.Y*game.CellSize) + 5
+			vector.DrawFilledCircle(screen, x, y, 2, facingColor, true)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+const (
+	TracerStep = 5    // Tracer duration change per settings adjustment, in ticks
+	TracerMax  = 60   // Longest a tracer can be set to stay bright
+	TrailAlpha = 0.35 // Opacity of a fresh trail relative to its tracer
+)
+
+// clampTracerTicks keeps a tracer duration within range, snapped to TracerStep
+func clampTracerTicks(n int) int {
+	n = (n + TracerStep/2) / TracerStep * TracerStep
+	return max(game.LaserDuration, min(TracerMax, n))
+}
+
+// adjustTracerTicks lengthens or shortens how long shots stay on screen, and
+// persists it
+func (g *Game) adjustTracerTicks(delta int) {
+	g.settings.TracerTicks = clampTracerTicks(g.settings.TracerTicks + delta)
+	g.ShowMessage("Shot tracers: %d ticks", g.settings.TracerTicks)
+	g.saveSettings()
+}
+
+// drawLaser draws a shot: its full tracer while that lasts, then a thin
+// trail fading out
+func drawLaser(screen *ebiten.Image, l *game.Laser, c color.RGBA) {
+	width := l.Width
+	if l.TTL == 0 {
+		fade := TrailAlpha * float64(l.Trail) / game.TrailDuration
+		c = color.RGBA{R: uint8(float64(c.R) * fade), G: uint8(float64(c.G) * fade), B: uint8(float64(c.B) * fade), A: uint8(float64(c.A) * fade)}
+		width = 1
+	}
+	vector.StrokeLine(screen, float32(l.FromX), float32(l.FromY), float32(l.ToX), float32(l.ToY), width, c, false)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var trapColor = color.RGBA{R: 255, G: 140, B: 0, A: 255}
+
+// drawTraps draws armed traps as small crosses
+func (g *Game) drawTraps(screen *ebiten.Image) {
+	const arm = game.CellSize / 5
+	for _, tr := range g.Traps {
+		cx, cy := game.CellCenter(tr.Cell.X, tr.Cell.Y)
+		x, y := float32(cx), float32(cy)
+		vector.StrokeLine(screen, x-arm, y-arm, x+arm, y+arm, 3, trapColor, true)
+		vector.StrokeLine(screen, x-arm, y+arm, x+arm, y-arm, 3, trapColor, true)
+	}
+}
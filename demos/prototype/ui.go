@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+	"golang.org/x/image/font/gofont/gomono"
+)
+
+const (
+	BaseFontSize  = 12   // UI text size in pixels at text scale 1
+	LineSpacing   = 1.3  // Line height as a multiple of the font size
+	TextScaleMin  = 1.0  // Smallest text scale setting
+	TextScaleMax  = 2.5  // Largest text scale setting
+	TextScaleStep = 0.25 // Text scale change per key press
+)
+
+var tooltipColor = color.RGBA{R: 0, G: 0, B: 0, A: 190}
+
+// uiFontSource is the typeface for all UI text
+var uiFontSource = mustFontSource(gomono.TTF)
+
+// mustFontSource parses an embedded font; it can only fail if the font data is corrupt
+func mustFontSource(ttf []byte) *text.GoTextFaceSource {
+	s, err := text.NewGoTextFaceSource(bytes.NewReader(ttf))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// uiFace returns the UI font at the player's text scale
+func (g *Game) uiFace() *text.GoTextFace {
+	return &text.GoTextFace{Source: uiFontSource, Size: BaseFontSize * g.settings.TextScale}
+}
+
+// lineHeight returns the height of one line of UI text in pixels
+func (g *Game) lineHeight() int {
+	return int(math.Ceil(g.uiFace().Size * LineSpacing))
+}
+
+// textWidth returns the width of a line of UI text in pixels
+func (g *Game) textWidth(s string) int {
+	return int(math.Ceil(text.Advance(s, g.uiFace())))
+}
+
+// drawText draws a line of UI text with its top-left corner at (x, y),
+// outlined so it stays readable over any tile
+func (g *Game) drawText(screen *ebiten.Image, s string, x, y int) {
+	face := g.uiFace()
+	pal := g.palette()
+	// Leading space above the glyphs, so lines stack at lineHeight
+	top := float64(y) + (float64(g.lineHeight())-face.Size)/2
+
+	op := &text.DrawOptions{}
+	op.ColorScale.ScaleWithColor(pal.TextOutline)
+	for _, d := range [][2]float64{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}} {
+		op.GeoM.Reset()
+		op.GeoM.Translate(float64(x)+d[0], top+d[1])
+		text.Draw(screen, s, face, op)
+	}
+
+	op.GeoM.Reset()
+	op.GeoM.Translate(float64(x), top)
+	op.ColorScale.Reset()
+	op.ColorScale.ScaleWithColor(pal.Text)
+	text.Draw(screen, s, face, op)
+}
+
+// drawStatus draws " | "-separated status fields from the top-left corner,
+// wrapping between fields when larger text doesn't fit on one line.
+// Returns the y just below the last line.
+func (g *Game) drawStatus(screen *ebiten.Image, status string) int {
+	y := 0
+	line := ""
+	for _, field := range strings.Split(status, " | ") {
+		next := field
+		if line != "" {
+			next = line + " | " + field
+		}
+		if line != "" && g.textWidth(next) > g.screenWidth() {
+			g.drawText(screen, line, 0, y)
+			y += g.lineHeight()
+			next = field
+		}
+		line = next
+	}
+	g.drawText(screen, line, 0, y)
+	return y + g.lineHeight()
+}
+
+// statsLines formats tower stats for a tooltip
+func statsLines(s game.TowerStats) []string {
+	return []string{
+		fmt.Sprintf("DMG %.0f  RNG %.1f", s.Damage, s.Range/game.CellSize),
+		fmt.Sprintf("DPS %.1f", s.DPS()),
+	}
+}
+
+// drawTooltip draws lines of text in a box next to the hovered cell,
+// flipping sides so it stays on screen
+func (g *Game) drawTooltip(screen *ebiten.Image, lines []string) {
+	// Hovered cell's screen corners
+	left, top := g.camera.toScreen(float64(g.hoverX*game.CellSize), float64(g.hoverY*game.CellSize))
+	right, _ := g.camera.toScreen(float64((g.hoverX+1)*game.CellSize), 0)
+
+	width := 0
+	for _, l := range lines {
+		width = max(width, g.textWidth(l))
+	}
+	width += 8
+	height := len(lines)*g.lineHeight() + 4
+
+	x := int(right) + 4
+	y := max(0, int(top))
+	if x+width > g.screenWidth() {
+		x = max(0, int(left)-width-4)
+	}
+	if y+height > g.screenHeight() {
+		y = g.screenHeight() - height
+	}
+
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), tooltipColor, false)
+	for i, l := range lines {
+		g.drawText(screen, l, x+4, y+2+i*g.lineHeight())
+	}
+}
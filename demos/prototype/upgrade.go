@@ -0,0 +1,33 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+const UpgradeKey = ebiten.KeySemicolon
+
+var levelColor = color.RGBA{R: 255, G: 215, B: 0, A: 255}
+
+// handleUpgradeInput upgrades the hovered tower on UpgradeKey
+func (g *Game) handleUpgradeInput() {
+	if g.hoverValid && inpututil.IsKeyJustPressed(UpgradeKey) {
+		g.UpgradeTower(g.hoverX, g.hoverY)
+	}
+}
+
+// drawLevels marks upgraded towers with a gold pip per level above 1, along
+// their bottom edge
+func (g *Game) drawLevels(screen *ebiten.Image) {
+	for _, t := range g.Towers {
+		for i := range t.Level - 1 {
+			x := float32((t.X+1)*game.CellSize) - 5 - float32(i)*6
+			y := float32((t.Y+1)*game.CellSize) - 5
+			vector.DrawFilledCircle(screen, x, y, 2, levelColor, true)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+// edgeRuns returns the [from, to) spans of 0..n where drawn(i) holds, merged
+// so a line along a solid stretch is drawn once
+func edgeRuns(n int, drawn func(i int) bool) [][2]int {
+	var runs [][2]int
+	from := -1
+	for i := 0; i <= n; i++ {
+		switch on := i < n && drawn(i); {
+		case on && from < 0:
+			from = i
+		case !on && from >= 0:
+			runs = append(runs, [2]int{from, i})
+			from = -1
+		}
+	}
+	return runs
+}
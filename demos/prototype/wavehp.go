@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+const WaveHealthBarWidth = 120 // Pixels
+
+// drawWaveHealth compares the wave's remaining health with the board's DPS,
+// in the top-right corner starting at y top. The bar fills as the time to
+// deal that much damage shrinks toward the time an enemy takes to walk the
+// shortest lane; full means the board keeps up. Returns the y just below it.
+func (g *Game) drawWaveHealth(screen *ebiten.Image, top int) int {
+	hp, dps := g.WaveHP(), g.BoardDPS()
+	if hp == 0 {
+		return top
+	}
+	shortest, _ := g.LaneLengths()
+	walk := float64(shortest*game.CellSize) / game.EnemySpeed / game.TPS // Seconds
+	ratio := 0.0
+	text := fmt.Sprintf("Wave HP %.0f | DPS %.0f", hp, dps)
+	if dps > 0 {
+		ratio = min(1, walk*dps/hp)
+		text += fmt.Sprintf(" | %.0fs to clear", hp/dps)
+	}
+	lh := g.lineHeight()
+	x := g.screenWidth() - g.textWidth(text) - 8
+	g.drawText(screen, text, x, top)
+
+	barX, barY := float32(g.screenWidth()-WaveHealthBarWidth-8), float32(top+lh)
+	vector.DrawFilledRect(screen, barX, barY, WaveHealthBarWidth, 6, menuPanelColor, false)
+	vector.DrawFilledRect(screen, barX, barY, WaveHealthBarWidth*float32(ratio), 6, dangerColor(1-ratio), false)
+	return top + lh + 10
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+const WaveReportBarWidth = 60 // Pixels of the bar for the kind that dealt the most
+
+// drawWaveReport breaks down the damage each tower kind dealt in the wave
+// just cleared, biggest first, down the right edge starting at y top.
+// Returns the y below it.
+func (g *Game) drawWaveReport(screen *ebiten.Image, top int) int {
+	if len(g.LastWaveDamage) == 0 {
+		return top
+	}
+	kinds := make([]game.TowerKind, 0, len(g.LastWaveDamage))
+	total, most := 0.0, 0.0
+	for k, d := range g.LastWaveDamage {
+		kinds = append(kinds, k)
+		total += d
+		most = max(most, d)
+	}
+	slices.SortFunc(kinds, func(a, b game.TowerKind) int {
+		return cmp.Or(cmp.Compare(g.LastWaveDamage[b], g.LastWaveDamage[a]), cmp.Compare(a, b))
+	})
+
+	lh := g.lineHeight()
+	header := fmt.Sprintf("Wave %d damage: %.0f", g.CurrentWave-1, total)
+	g.drawText(screen, header, g.screenWidth()-g.textWidth(header)-8, top)
+	y := top + lh
+	for _, k := range kinds {
+		d := g.LastWaveDamage[k]
+		text := fmt.Sprintf("%s %.0f (%.0f%%)", game.TowerTypes[k].Name, d, d/total*100)
+		x := g.screenWidth() - g.textWidth(text) - 8
+		w := float32(WaveReportBarWidth * d / most)
+		vector.DrawFilledRect(screen, float32(x-6)-w, float32(y+lh/4), w, float32(lh/2), game.TowerTypes[k].Color, false)
+		g.drawText(screen, text, x, y)
+		y += lh
+	}
+	return y
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/toejough/claude-td/demos/prototype/game"
+)
+
+var waveKilledColor = color.RGBA{R: 80, G: 200, B: 90, A: 255}
+
+var waveAliveColor = color.RGBA{R: 230, G: 70, B: 60, A: 255}
+
+var waveQueuedColor = color.RGBA{R: 90, G: 90, B: 100, A: 255}
+
+// drawWavePreview lists the composition and timing of upcoming waves down the
+// right edge, starting at y top
+func (g *Game) drawWavePreview(screen *ebiten.Image, top int) {
+	lh := g.lineHeight()
+	y := top
+	line := func(text string, dot *game.EnemyType) {
+		x := g.screenWidth() - g.textWidth(text) - 24
+		if dot != nil {
+			vector.DrawFilledCircle(screen, float32(x+8), float32(y+lh/2), 5, dot.Color, true)
+		}
+		g.drawText(screen, text, x+18, y)
+		y += lh
+	}
+	for _, w := range g.PreviewWaves(g.lookahead) {
+		size := g.WaveSize(w)
+		header := fmt.Sprintf("Wave %d: %d over %ds", w, size, (size-1)*game.SpawnInterval/game.TPS)
+		if w == g.CurrentWave {
+			header += fmt.Sprintf(", in %ds", g.WaveDelay/game.TPS+1)
+		}
+		if g.IsMegaWave(w) {
+			header = "MEGA " + header
+		}
+		line(header, nil)
+		for k, n := range g.WaveComposition(w) {
+			if n > 0 {
+				line(fmt.Sprintf("%d %s", n, game.EnemyTypes[k].Name), &game.EnemyTypes[k])
+			}
+		}
+	}
+}
+
+// drawWaveProgress draws a slim bar across the screen at y, split into the
+// wave's killed, alive, and still-to-spawn enemies. Returns the y below it.
+func (g *Game) drawWaveProgress(screen *ebiten.Image, y int) int {
+	alive := len(g.Enemies)
+	total := g.WaveKills + alive + g.EnemiesThisWave
+	if total == 0 {
+		return y
+	}
+	x := float32(0)
+	for _, seg := range []struct {
+		n int
+		c color.RGBA
+	}{{g.WaveKills, waveKilledColor}, {alive, waveAliveColor}, {g.EnemiesThisWave, waveQueuedColor}} {
+		w := float32(g.screenWidth()) * float32(seg.n) / float32(total)
+		vector.DrawFilledRect(screen, x, float32(y), w, game.WaveProgressHeight, seg.c, false)
+		x += w
+	}
+	return y + game.WaveProgressHeight + 2
+}